@@ -87,6 +87,30 @@ typedef struct {
 #define POS_LEFT 0.0
 #define POS_RIGHT 1.0
 
+// Registry a handle belongs to, see HandleKind
+typedef enum {
+    HANDLE_KIND_INVALID = 0,
+    HANDLE_KIND_STYLE = 1,
+    HANDLE_KIND_TABLE = 2,
+    HANDLE_KIND_TREE = 3,
+    HANDLE_KIND_LIST = 4,
+    HANDLE_KIND_THEME = 5
+} CHandleKind;
+
+// Element types of read-only input arrays, e.g. for StyleRenderBatch
+typedef const uint64_t CHandleIn;
+typedef const char* CStringIn;
+
+// Result of the *_Into render functions
+typedef enum {
+    RENDER_OK = 0,               // output and its NUL terminator were written
+    RENDER_BUFFER_TOO_SMALL = 1, // nothing written, *needed holds the size
+    RENDER_ERROR = 2             // invalid arguments, see the log
+} CRenderStatus;
+
+// Tab width that keeps tabs as is, for StyleTabWidth
+#define TAB_WIDTH_NO_CONVERSION -1
+
 // Color profile types
 typedef enum {
     PROFILE_ASCII,
@@ -103,6 +127,75 @@ typedef enum {
     LOG_DEBUG = 3
 } CLogLevel;
 
+// List enumerator types for ListSetEnumerator
+typedef enum {
+    LIST_ENUM_BULLET = 0,
+    LIST_ENUM_DASH = 1,
+    LIST_ENUM_ALPHABET = 2,
+    LIST_ENUM_ARABIC = 3,
+    LIST_ENUM_ROMAN = 4,
+    LIST_ENUM_ASTERISK = 5,
+    LIST_ENUM_CHECKBOX = 6
+} CListEnumeratorType;
+
+// List enumerator callbacks follow the same borrowing rules as tree callbacks
+typedef const char* (*CListEnumeratorFunc)(int items_count, int index, void* userdata);
+
+// Tree enumerator types for TreeSetEnumerator
+typedef enum {
+    TREE_ENUM_DEFAULT = 0,
+    TREE_ENUM_ROUNDED = 1,
+    TREE_ENUM_ASCII = 2
+} CTreeEnumeratorType;
+
+// Tree indenter types for TreeSetIndenter
+typedef enum {
+    TREE_INDENT_DEFAULT = 0,
+    TREE_INDENT_SPACES = 1,
+    TREE_INDENT_ASCII = 2,
+    TREE_INDENT_BLANK = 3
+} CTreeIndenterType;
+
+// Tree callbacks receive the number of siblings and the index of the node
+// being drawn. The returned string is borrowed: it is copied immediately and
+// must stay valid only until the callback returns.
+typedef const char* (*CTreeEnumeratorFunc)(int children_count, int index, void* userdata);
+typedef const char* (*CTreeIndenterFunc)(int children_count, int index, void* userdata);
+
+// Style callbacks pick a style for the item at index and return its style ID.
+// Returning 0 renders the item unstyled.
+typedef uint64_t (*CStyleFunc)(int children_count, int index, void* userdata);
+
+// Theme document formats for LoadTheme
+typedef enum {
+    THEME_FORMAT_AUTO = 0,
+    THEME_FORMAT_JSON = 1,
+    THEME_FORMAT_TOML = 2,
+    THEME_FORMAT_YAML = 3
+} CThemeFormat;
+
+// Style transforms rewrite a style's content before it is rendered. content
+// is owned by the library and valid only during the call. Return a new
+// string allocated with malloc, which the library frees once it has copied
+// it, or NULL to leave the content unchanged. A transform may instead
+// rewrite content in place, without writing past its terminating NUL, and
+// return it. Transforms run on whichever thread renders the style, so
+// userdata must outlive the style and any style derived from it.
+typedef char* (*CTransformFunc)(const char* content, void* userdata);
+
+// Options for RenderToSVG. Zero values and NULL strings select the defaults
+// noted below, so a zero-initialized struct is a valid configuration.
+typedef struct {
+    double font_size;          // in pixels, default 14
+    const char* font_family;   // CSS font-family list, default a monospace stack
+    const char* foreground;    // default text color, default "#c0c0c0"
+    const char* background;    // terminal background, default "#000000"
+    const char* palette[16];   // ANSI colors 0-15 as "#rrggbb", NULL entries keep the xterm defaults
+    int padding;               // space around the text in pixels, default 16, negative for none
+    int window_chrome;         // nonzero draws a window frame with a title bar
+    const char* title;         // title shown in the window chrome
+} CSVGOptions;
+
 // Renderer context
 typedef struct {
     void* Output;
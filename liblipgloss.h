@@ -49,6 +49,10 @@ typedef struct { const char *p; ptrdiff_t n; } _GoString_;
 #include <stdint.h>
 #include "lipgloss_types.h"
 
+static inline const char* callListEnumerator(CListEnumeratorFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
+
 #line 1 "cgo-generated-wrapper"
 
 #line 3 "position_wrapper.go"
@@ -135,6 +139,14 @@ typedef struct { const char *p; ptrdiff_t n; } _GoString_;
 #include <stdint.h>
 #include "lipgloss_types.h"
 
+static inline const char* callTreeEnumerator(CTreeEnumeratorFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
+
+static inline const char* callTreeIndenter(CTreeIndenterFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
+
 #line 1 "cgo-generated-wrapper"
 
 #line 3 "utils.go"
@@ -142,6 +154,148 @@ typedef struct { const char *p; ptrdiff_t n; } _GoString_;
 #include <stdlib.h>
 #include <stdint.h>
 #include <stdbool.h>
+#include <string.h>
+#include "lipgloss_types.h"
+
+static inline uint64_t callStyleFunc(CStyleFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "markdown_wrapper.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "theme_registry.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "theme_loader.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "style_json.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "style_css.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "ansi_html.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "ansi_svg.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "ansi_wrapper.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "style_transform.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+static inline char* callTransform(CTransformFunc fn, const char* content, void* userdata) {
+	return fn(content, userdata);
+}
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "color_blend.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "color_adjust.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "diagnostics.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "handles.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "scope.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include <pthread.h>
+#include "lipgloss_types.h"
+
+static inline uint64_t currentThreadID(void) {
+	return (uint64_t)(uintptr_t)pthread_self();
+}
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "render_into.go"
+
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+#line 1 "cgo-generated-wrapper"
+
+#line 3 "render_cache.go"
+
+#include <stdlib.h>
+#include <stdint.h>
 #include "lipgloss_types.h"
 
 #line 1 "cgo-generated-wrapper"
@@ -290,8 +444,18 @@ extern char* StyleRunes(char* str, int* indices, int indicesLen, void* matchedHa
 extern int Width(char* str);
 extern uint64_t NewList();
 extern void ListAddItem(uint64_t id, char* item);
+extern void ListAddSublist(uint64_t parentID, uint64_t childID);
+extern int ListItemCount(uint64_t id);
+extern int ListSetItemChecked(uint64_t id, int index, int checked);
+extern int ListSetItemHidden(uint64_t id, int index, int hide);
+extern void ListSetHidden(uint64_t id, int hide);
+extern void ListSetOffset(uint64_t id, int start, int end);
 extern void ListSetEnumerator(uint64_t id, int enumeratorType);
-extern void ListSetItemStyle(uint64_t id, char* style);
+extern void ListSetEnumeratorFunc(uint64_t id, CListEnumeratorFunc fn, void* userdata);
+extern void ListSetItemStyle(uint64_t id, uint64_t styleID);
+extern void ListSetItemStyleFunc(uint64_t id, CStyleFunc fn, void* userdata);
+extern void ListSetEnumeratorStyle(uint64_t id, uint64_t styleID);
+extern void ListSetEnumeratorStyleFunc(uint64_t id, CStyleFunc fn, void* userdata);
 extern char* RenderList(uint64_t id);
 extern void FreeList(uint64_t id);
 extern float PositionTop();
@@ -343,6 +507,7 @@ extern void FreeStyle(uint64_t id);
 extern void FreeString(char* str);
 extern char* GetStyleStats();
 extern char* StyleRender(uint64_t id, char* str);
+extern size_t StyleRenderBatch(CHandleIn* styles, CStringIn* texts, size_t n, char** out);
 extern int StyleInherited(uint64_t id);
 extern char* StyleString(uint64_t id);
 extern uint64_t StyleInherit(uint64_t baseID, uint64_t inheritID);
@@ -351,6 +516,7 @@ extern uint64_t StyleInherit(uint64_t baseID, uint64_t inheritID);
 //
 extern char* StyleCleanup();
 extern uint64_t StyleSetString(uint64_t id, char* str);
+extern uint64_t StyleSetStrings(uint64_t id, char** strs, int count);
 extern char* StyleGetValue(uint64_t id);
 extern uint64_t StyleBold(uint64_t id, int v);
 extern uint64_t StyleItalic(uint64_t id, int v);
@@ -359,6 +525,33 @@ extern uint64_t StyleStrikethrough(uint64_t id, int v);
 extern uint64_t StyleReverse(uint64_t id, int v);
 extern uint64_t StyleBlink(uint64_t id, int v);
 extern uint64_t StyleFaint(uint64_t id, int v);
+extern uint64_t StyleUnderlineSpaces(uint64_t id, int v);
+extern uint64_t StyleStrikethroughSpaces(uint64_t id, int v);
+extern int StyleGetBold(uint64_t id);
+extern int StyleGetItalic(uint64_t id);
+extern int StyleGetUnderline(uint64_t id);
+extern int StyleGetStrikethrough(uint64_t id);
+extern int StyleGetReverse(uint64_t id);
+extern int StyleGetBlink(uint64_t id);
+extern int StyleGetFaint(uint64_t id);
+extern int StyleGetUnderlineSpaces(uint64_t id);
+extern int StyleGetStrikethroughSpaces(uint64_t id);
+extern int StyleGetColorWhitespace(uint64_t id);
+extern int StyleGetInline(uint64_t id);
+extern int StyleGetTabWidth(uint64_t id);
+extern uint64_t StyleUnsetBold(uint64_t id);
+extern uint64_t StyleUnsetItalic(uint64_t id);
+extern uint64_t StyleUnsetUnderline(uint64_t id);
+extern uint64_t StyleUnsetStrikethrough(uint64_t id);
+extern uint64_t StyleUnsetReverse(uint64_t id);
+extern uint64_t StyleUnsetBlink(uint64_t id);
+extern uint64_t StyleUnsetFaint(uint64_t id);
+extern uint64_t StyleUnsetUnderlineSpaces(uint64_t id);
+extern uint64_t StyleUnsetStrikethroughSpaces(uint64_t id);
+extern uint64_t StyleUnsetColorWhitespace(uint64_t id);
+extern uint64_t StyleUnsetInline(uint64_t id);
+extern uint64_t StyleUnsetTabWidth(uint64_t id);
+extern uint64_t StyleUnsetString(uint64_t id);
 extern char* GetTextStyleInfo(uint64_t id);
 extern uint64_t NewTable();
 extern void TableAddHeaders(uint64_t id, char** headers, int count);
@@ -369,15 +562,77 @@ extern void TableSetBorder(uint64_t id, int borderType);
 extern char* RenderTable(uint64_t id);
 extern void FreeTable(uint64_t id);
 extern uint64_t NewTree();
+extern void TreeSetRoot(uint64_t id, char* root);
 extern void TreeAddChildValue(uint64_t parentID, char* value);
 extern void TreeAddChildTree(uint64_t parentID, uint64_t childID);
+extern int TreeChildCount(uint64_t id);
+extern int TreeRemoveChild(uint64_t id, int index);
+extern int TreeReplaceChildValue(uint64_t id, int index, char* value);
+extern int TreeReplaceChildTree(uint64_t id, int index, uint64_t childID);
+extern void TreeSetHidden(uint64_t id, int hide);
+extern int TreeSetChildHidden(uint64_t id, int index, int hide);
 extern void TreeSetEnumerator(uint64_t id, int enumType);
+extern void TreeSetEnumeratorFunc(uint64_t id, CTreeEnumeratorFunc fn, void* userdata);
 extern void TreeSetIndenter(uint64_t id, int indentType);
-extern void TreeSetItemStyle(uint64_t id, char* style);
+extern void TreeSetIndenterFunc(uint64_t id, CTreeIndenterFunc fn, void* userdata);
+extern void TreeSetItemStyle(uint64_t id, uint64_t styleID);
+extern void TreeSetItemStyleFunc(uint64_t id, CStyleFunc fn, void* userdata);
+extern void TreeSetRootStyle(uint64_t id, uint64_t styleID);
+extern void TreeSetEnumeratorStyle(uint64_t id, uint64_t styleID);
+extern void TreeSetEnumeratorStyleFunc(uint64_t id, CStyleFunc fn, void* userdata);
 extern char* RenderTree(uint64_t id);
 extern void FreeTree(uint64_t id);
 extern void SetLogLevel(int level);
 extern char* GetMemoryLeaks();
+extern void SetMemoryTracking(int enabled);
+extern char* GetMemoryStats();
+extern char* RenderMarkdown(char* markdown, uint64_t themeID);
+extern uint64_t NewTheme();
+extern int ThemeSetStyle(uint64_t themeID, char* name, uint64_t styleID);
+extern uint64_t ThemeGetStyle(uint64_t themeID, char* name);
+extern void FreeTheme(uint64_t themeID);
+extern uint64_t LoadTheme(char* source, int format, char** errOut);
+extern uint64_t LoadThemeFile(char* path, char** errOut);
+extern char* StyleToJSON(uint64_t id);
+extern uint64_t StyleFromJSON(char* data, char** errOut);
+extern uint64_t StyleFromCSS(char* css, char** errOut);
+
+extern char* ANSIToHTML(char* text);
+extern char* RenderToSVG(char* ansiText, CSVGOptions* options);
+extern char* Strip(char* str);
+extern char* Truncate(char* str, int length, char* tail);
+extern char* TruncateLeft(char* str, int n, char* prefix);
+extern char* Wordwrap(char* str, int limit, char* breakpoints);
+extern char* Hardwrap(char* str, int limit, int preserveSpace);
+extern char* Cut(char* str, int left, int right);
+extern uint64_t StyleTransform(uint64_t id, CTransformFunc fn, void* userdata);
+extern uint64_t StyleUnsetTransform(uint64_t id);
+extern char* BlendColor(char* a, char* b, double t);
+extern int BlendColors(char** colors, int count, int n, char** out);
+extern char* RenderGradient(char* text, char** colors, int n);
+extern char* ColorLighten(char* color, double amount);
+extern char* ColorDarken(char* color, double amount);
+extern char* ColorSaturate(char* color, double amount);
+extern double ColorContrastRatio(char* foreground, char* background);
+extern char* ColorReadableText(char* background, char** candidates, int count);
+extern int ColorToANSI256(char* color);
+extern int ColorToANSI16(char* color);
+extern char* GetDiagnostics();
+extern uint64_t GetLiveHandleCount();
+extern int HandleKind(uint64_t id);
+extern int HandleIsValid(uint64_t id);
+extern char* HandleError(uint64_t id);
+extern uint64_t BeginScope();
+extern void EndScope(uint64_t id);
+extern int ScopeEscape(uint64_t id);
+extern int ScopeEscapeString(char* str);
+extern int StyleRenderInto(uint64_t id, char* str, char* buf, size_t capacity, size_t* needed);
+extern int JoinHorizontalInto(double pos, char* str1, char* str2, char* buf, size_t capacity, size_t* needed);
+extern int PlaceInto(int width, int height, double hPos, double vPos, char* str, char* buf, size_t capacity, size_t* needed);
+extern int RenderTableInto(uint64_t id, char* buf, size_t capacity, size_t* needed);
+extern void SetRenderCache(int maxEntries, size_t maxBytes);
+extern void ClearRenderCache();
+extern char* GetRenderCacheStats();
 
 #ifdef __cplusplus
 }
@@ -2,6 +2,7 @@
 #include <stdint.h>
 #include <stdbool.h>
 #include <string.h>
+#include <pthread.h>
 #include "liblipgloss.h"
 
 void test_border_memory() {
@@ -226,6 +227,112 @@ void test_tree_memory() {
     FreeTree(tree);
 }
 
+// Concurrent tracking
+
+static void* render_worker(void* arg) {
+    (void)arg;
+    uint64_t style = NewStyle();
+    for (int i = 0; i < 100; i++) {
+        char* rendered = StyleRender(style, "Concurrent");
+        FreeString(rendered);
+    }
+    FreeStyle(style);
+    return NULL;
+}
+
+void test_concurrent_tracking() {
+    printf("\n=== Testing Concurrent Tracking ===\n");
+    pthread_t threads[4];
+    for (int i = 0; i < 4; i++) {
+        pthread_create(&threads[i], NULL, render_worker, NULL);
+    }
+    for (int i = 0; i < 4; i++) {
+        pthread_join(threads[i], NULL);
+    }
+
+    // Strings allocated while tracking is off are freed without warnings
+    SetMemoryTracking(0);
+    char* untracked = Place(10, 1, 0.5, 0.5, "Off");
+    SetMemoryTracking(1);
+    FreeString(untracked);
+
+    char* stats = GetMemoryStats();
+    printf("%s", stats);
+    FreeString(stats);
+}
+
+// Diagnostics
+
+void test_diagnostics() {
+    printf("\n=== Testing Diagnostics ===\n");
+    uint64_t before = GetLiveHandleCount();
+    uint64_t style = NewStyle();
+    uint64_t bold = StyleBold(style, 1);
+    uint64_t table = NewTable();
+    printf("New live handles: %llu\n", (unsigned long long)(GetLiveHandleCount() - before));
+
+    char* report = GetDiagnostics();
+    printf("Reports StyleBold origin: %s\n", strstr(report, "\"op\":\"StyleBold\"") ? "yes" : "no");
+    printf("Reports table registry: %s\n", strstr(report, "\"name\":\"table\"") ? "yes" : "no");
+    FreeString(report);
+
+    FreeTable(table);
+    FreeStyle(bold);
+    FreeStyle(style);
+    printf("Live handles after free: %llu\n", (unsigned long long)(GetLiveHandleCount() - before));
+}
+
+// Scopes
+
+static void* scoped_worker(void* arg) {
+    uint64_t* kept = arg;
+    uint64_t scope = BeginScope();
+    uint64_t style = StyleBold(NewStyle(), 1);
+    StyleRender(style, "Thread frame");
+    *kept = NewStyle();
+    ScopeEscape(*kept);
+    EndScope(scope);
+    return NULL;
+}
+
+void test_scopes() {
+    printf("\n=== Testing Scopes ===\n");
+    uint64_t before = GetLiveHandleCount();
+
+    uint64_t frame = BeginScope();
+    uint64_t base = NewStyle();
+    uint64_t title = StyleForeground(StyleBold(base, 1), "#FF0000");
+    char* rendered = StyleRender(title, "Frame");
+    char* kept_string = StyleRender(base, "Kept");
+    ScopeEscapeString(kept_string);
+    uint64_t kept_style = StylePadding(base, 1, 1, 1, 1);
+    ScopeEscape(kept_style);
+    FreeString(rendered);  // freeing by hand inside a scope is fine
+
+    uint64_t inner = BeginScope();
+    uint64_t table = NewTable();
+    EndScope(inner);
+    printf("Inner scope freed table: %d\n", !HandleIsValid(table));
+
+    uint64_t nested = BeginScope();
+    NewList();
+    EndScope(frame);  // ends the nested scope too
+    printf("Ending outer scope ends inner ones: live=%llu\n", (unsigned long long)(GetLiveHandleCount() - before));
+    printf("Escaped style valid: %d, escaped string: %s\n", HandleIsValid(kept_style), kept_string);
+    printf("Scoped style freed: %d\n", !HandleIsValid(title));
+    EndScope(nested);  // already ended, logs an error
+
+    pthread_t thread;
+    uint64_t thread_kept = 0;
+    pthread_create(&thread, NULL, scoped_worker, &thread_kept);
+    pthread_join(thread, NULL);
+    printf("Thread scope kept one style: live=%llu\n", (unsigned long long)(GetLiveHandleCount() - before));
+
+    FreeStyle(thread_kept);
+    FreeStyle(kept_style);
+    FreeString(kept_string);
+}
+
 int main() {
     // Set log level to debug to track allocations
     SetLogLevel(3);  // LogLevelDebug
@@ -241,6 +348,9 @@ int main() {
     
     // Run stress test
     test_stress_memory();
+    test_concurrent_tracking();
+    test_diagnostics();
+    test_scopes();
     
     // Check final memory status
     print_memory_status();
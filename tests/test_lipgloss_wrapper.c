@@ -1,6 +1,8 @@
 #include <stdio.h>
 #include <stdint.h>
 #include <stdbool.h>
+#include <string.h>
+#include <stdlib.h>
 #include "liblipgloss.h"
 
 void test_basic_utilities() {
@@ -388,21 +390,134 @@ void test_list_enumerators() {
     FreeList(list);
 }
 
+static uint64_t zebra_styles[2];
+
+static uint64_t zebra_style(int children_count, int index, void* userdata) {
+    (void)children_count;
+    (void)userdata;
+    return zebra_styles[index % 2];
+}
+
+void test_list_styles() {
+    printf("\n=== Testing List Styles ===\n");
+    uint64_t base_style = NewStyle();
+    uint64_t bold = StyleBold(base_style, 1);
+    uint64_t item_style = StylePaddingLeft(bold, 1);
+    uint64_t enum_style = StyleForeground(base_style, "#00FFFF");
+    zebra_styles[0] = StyleBackground(base_style, "#303030");
+    zebra_styles[1] = 0;
+
+    uint64_t list = NewList();
+    ListAddItem(list, "Apples");
+    ListAddItem(list, "Bananas");
+    ListAddItem(list, "Oranges");
+
+    printf("Static Item and Enumerator Styles:\n");
+    ListSetItemStyle(list, item_style);
+    ListSetEnumeratorStyle(list, enum_style);
+    char* styled = RenderList(list);
+    printf("%s\n", styled);
+    FreeString(styled);
+
+    printf("Item Style Callback:\n");
+    ListSetItemStyleFunc(list, zebra_style, NULL);
+    char* zebra = RenderList(list);
+    printf("%s\n", zebra);
+    FreeString(zebra);
+
+    FreeList(list);
+    FreeStyle(zebra_styles[0]);
+    FreeStyle(enum_style);
+    FreeStyle(item_style);
+    FreeStyle(bold);
+    FreeStyle(base_style);
+}
+
+static const char* step_enumerator(int items_count, int index, void* userdata) {
+    static char buf[32];
+    (void)userdata;
+    snprintf(buf, sizeof(buf), "(%d/%d)", index + 1, items_count);
+    return buf;
+}
+
+void test_nested_lists() {
+    printf("\n=== Testing Nested Lists ===\n");
+    uint64_t todo = NewList();
+    ListSetEnumerator(todo, LIST_ENUM_CHECKBOX);
+    ListAddItem(todo, "Write docs");
+    ListAddItem(todo, "Groceries");
+
+    uint64_t groceries = NewList();
+    ListSetEnumerator(groceries, LIST_ENUM_CHECKBOX);
+    ListAddItem(groceries, "Milk");
+    ListAddItem(groceries, "Eggs");
+    ListSetItemChecked(groceries, 1, 1);
+    ListAddSublist(todo, groceries);
+
+    ListAddItem(todo, "Ship release");
+    ListSetItemChecked(todo, 0, 1);
+
+    printf("Nested Checklist:\n");
+    char* nested = RenderList(todo);
+    printf("%s\n", nested);
+    FreeString(nested);
+
+    printf("Asterisk Sublist:\n");
+    ListSetEnumerator(groceries, LIST_ENUM_ASTERISK);
+    char* asterisk = RenderList(todo);
+    printf("%s\n", asterisk);
+    FreeString(asterisk);
+
+    printf("Collapsed Sublist:\n");
+    ListSetHidden(groceries, 1);
+    char* collapsed = RenderList(todo);
+    printf("%s\n", collapsed);
+    FreeString(collapsed);
+
+    printf("Offset and Hidden Item:\n");
+    ListAddItem(todo, "Celebrate");
+    ListSetOffset(todo, 1, 0);
+    ListSetItemHidden(todo, 2, 1);
+    char* windowed = RenderList(todo);
+    printf("%s\n", windowed);
+    FreeString(windowed);
+
+    printf("Callback Enumerator:\n");
+    uint64_t steps = NewList();
+    ListAddItem(steps, "Configure");
+    ListAddItem(steps, "Build");
+    ListAddItem(steps, "Install");
+    ListSetEnumeratorFunc(steps, step_enumerator, NULL);
+    char* custom = RenderList(steps);
+    printf("%s\n", custom);
+    FreeString(custom);
+
+    FreeList(steps);
+    FreeList(groceries);
+    FreeList(todo);
+}
+
 // Trees
 
 void test_tree() {
     printf("\n=== Testing Tree Rendering ===\n");
+    uint64_t base_style = NewStyle();
+    uint64_t red = StyleForeground(base_style, "#FF0000");
+    uint64_t green = StyleForeground(base_style, "#00FF00");
+    uint64_t blue = StyleForeground(base_style, "#0000FF");
+    uint64_t magenta = StyleForeground(base_style, "#FF00FF");
+
     uint64_t tree = NewTree();
-    TreeSetItemStyle(tree, "#FF0000");
+    TreeSetItemStyle(tree, red);
     TreeAddChildValue(tree, "Foo");
     uint64_t bar = NewTree();
-    TreeSetItemStyle(bar, "#00FF00");
+    TreeSetItemStyle(bar, green);
     TreeAddChildValue(bar, "Bar");
     uint64_t qux = NewTree();
-    TreeSetItemStyle(qux, "#0000FF");
+    TreeSetItemStyle(qux, blue);
     TreeAddChildValue(qux, "Qux");
     uint64_t quux = NewTree();
-    TreeSetItemStyle(quux, "#FF00FF");
+    TreeSetItemStyle(quux, magenta);
     TreeAddChildValue(quux, "Quux");
     TreeAddChildValue(quux, "Foo");
     TreeAddChildValue(quux, "Bar");
@@ -416,6 +531,12 @@ void test_tree() {
     printf("%s\n", result);
     FreeString(result);
     FreeTree(tree);
+
+    FreeStyle(red);
+    FreeStyle(green);
+    FreeStyle(blue);
+    FreeStyle(magenta);
+    FreeStyle(base_style);
 }
 
 void test_tree_enumerators() {
@@ -452,6 +573,699 @@ void test_tree_enumerators() {
     FreeTree(tree);
 }
 
+void test_tree_mutation() {
+    printf("\n=== Testing Tree Mutation ===\n");
+    uint64_t tree = NewTree();
+    TreeSetRoot(tree, "~/projects");
+
+    uint64_t base_style = NewStyle();
+    uint64_t root_style = StyleBold(base_style, 1);
+    TreeSetRootStyle(tree, root_style);
+    uint64_t gray_style = StyleForeground(base_style, "#888888");
+    uint64_t enum_style = StylePaddingRight(gray_style, 1);
+    TreeSetEnumeratorStyle(tree, enum_style);
+
+    uint64_t src = NewTree();
+    TreeSetRoot(src, "src");
+    TreeAddChildValue(src, "main.c");
+    TreeAddChildValue(src, "util.c");
+    TreeAddChildTree(tree, src);
+    TreeAddChildValue(tree, "README.md");
+    TreeAddChildValue(tree, "Makefile");
+
+    printf("Expanded (%d children):\n", TreeChildCount(tree));
+    char* expanded = RenderTree(tree);
+    printf("%s\n", expanded);
+    FreeString(expanded);
+
+    printf("Collapsed src, renamed Makefile, removed README.md:\n");
+    TreeSetHidden(src, 1);
+    TreeAddChildValue(tree, "src/ (collapsed)");
+    TreeReplaceChildValue(tree, 2, "CMakeLists.txt");
+    TreeRemoveChild(tree, 1);
+    char* collapsed = RenderTree(tree);
+    printf("%s\n", collapsed);
+    FreeString(collapsed);
+
+    printf("Hidden child at index 1:\n");
+    TreeSetChildHidden(tree, 1, 1);
+    char* hidden = RenderTree(tree);
+    printf("%s\n", hidden);
+    FreeString(hidden);
+
+    printf("Remove out of range returns: %d\n", TreeRemoveChild(tree, 42));
+
+    FreeStyle(root_style);
+    FreeStyle(gray_style);
+    FreeStyle(enum_style);
+    FreeStyle(base_style);
+    FreeTree(src);
+    FreeTree(tree);
+}
+
+static const char* arrow_enumerator(int children_count, int index, void* userdata) {
+    (void)userdata;
+    return index == children_count - 1 ? "=>" : "->";
+}
+
+static const char* dotted_indenter(int children_count, int index, void* userdata) {
+    (void)children_count;
+    (void)index;
+    return (const char*)userdata;
+}
+
+void test_tree_custom_enumerators() {
+    printf("\n=== Testing Custom Tree Enumerators ===\n");
+    uint64_t tree = NewTree();
+    TreeAddChildValue(tree, "Foo");
+    uint64_t bar = NewTree();
+    TreeSetRoot(bar, "Bar");
+    TreeAddChildValue(bar, "Qux");
+    TreeAddChildValue(bar, "Quux");
+    TreeAddChildTree(tree, bar);
+    TreeAddChildValue(tree, "Baz");
+
+    printf("ASCII Enumerator and Indenter:\n");
+    TreeSetEnumerator(tree, TREE_ENUM_ASCII);
+    TreeSetIndenter(tree, TREE_INDENT_ASCII);
+    char* ascii = RenderTree(tree);
+    printf("%s\n", ascii);
+    FreeString(ascii);
+
+    printf("Blank Indenter:\n");
+    TreeSetIndenter(tree, TREE_INDENT_BLANK);
+    char* blank = RenderTree(tree);
+    printf("%s\n", blank);
+    FreeString(blank);
+
+    printf("Callback Enumerator and Indenter:\n");
+    TreeSetEnumeratorFunc(tree, arrow_enumerator, NULL);
+    TreeSetIndenterFunc(tree, dotted_indenter, ". ");
+    char* custom = RenderTree(tree);
+    printf("%s\n", custom);
+    FreeString(custom);
+
+    FreeTree(bar);
+    FreeTree(tree);
+}
+
+// Markdown
+
+static char markdown_doc[] =
+    "# Release Notes\n"
+    "\n"
+    "Version **2.0** brings *faster* rendering, `RenderMarkdown` and ~~no~~ fewer bugs.\n"
+    "See [the docs](https://example.com/docs).\n"
+    "\n"
+    "## Changes\n"
+    "\n"
+    "- Themes\n"
+    "- Markdown\n"
+    "  1. Headings\n"
+    "  2. Tables\n"
+    "- Lists\n"
+    "\n"
+    "> Quoted text\n"
+    "\n"
+    "| Feature | Status |\n"
+    "|:--------|-------:|\n"
+    "| Lists   | done   |\n"
+    "| Tables  | done   |\n"
+    "\n"
+    "---\n"
+    "\n"
+    "```\n"
+    "make install\n"
+    "```\n";
+
+void test_markdown() {
+    printf("\n=== Testing Markdown ===\n");
+    printf("Default Styles:\n");
+    char* plain = RenderMarkdown(markdown_doc, 0);
+    printf("%s\n", plain);
+    FreeString(plain);
+
+    uint64_t base_style = NewStyle();
+    uint64_t heading = StyleForeground(base_style, "#FF5F87");
+    uint64_t strong = StyleUnderline(base_style, 1);
+    uint64_t bullet = StyleForeground(base_style, "#00FFFF");
+
+    uint64_t theme = NewTheme();
+    ThemeSetStyle(theme, "heading", heading);
+    ThemeSetStyle(theme, "strong", strong);
+    ThemeSetStyle(theme, "list_enumerator", bullet);
+
+    // The theme keeps its own copies, so the originals can go right away
+    FreeStyle(bullet);
+    FreeStyle(strong);
+    FreeStyle(heading);
+
+    printf("Themed Styles:\n");
+    char* themed = RenderMarkdown(markdown_doc, theme);
+    printf("%s\n", themed);
+    FreeString(themed);
+
+    uint64_t theme_heading = ThemeGetStyle(theme, "heading");
+    char* sample = StyleRender(theme_heading, "Theme heading style");
+    printf("%s\n", sample);
+    FreeString(sample);
+
+    FreeTheme(theme);
+    FreeStyle(base_style);
+}
+
+static char theme_json[] =
+    "{\n"
+    "  \"styles\": {\n"
+    "    \"base\": {\"foreground\": {\"light\": \"#333333\", \"dark\": \"#EEEEEE\"}, \"padding\": [0, 1]},\n"
+    "    \"title\": {\"inherit\": \"base\", \"bold\": true, \"align\": \"center\", \"width\": 24,\n"
+    "              \"border\": {\"style\": \"rounded\", \"foreground\": \"63\"}},\n"
+    "    \"warning\": {\"inherit\": \"base\", \"background\": {\"true_color\": \"#FF8800\", \"ansi256\": \"208\", \"ansi\": \"3\"}}\n"
+    "  }\n"
+    "}\n";
+
+static char theme_yaml[] =
+    "styles:\n"
+    "  base:\n"
+    "    italic: true\n"
+    "  note:\n"
+    "    inherit: base\n"
+    "    margin: [0, 2]\n"
+    "    border: normal\n";
+
+static char theme_toml[] =
+    "[styles.base]\n"
+    "underline = true\n"
+    "\n"
+    "[styles.label]\n"
+    "inherit = \"base\"\n"
+    "padding = 1\n"
+    "border = \"thick\"\n";
+
+static char theme_bad[] =
+    "{\"styles\": {\"title\": {\"padding\": [1, -2]}}}";
+
+static char theme_cycle[] =
+    "styles:\n"
+    "  a: {inherit: b}\n"
+    "  b: {inherit: a}\n";
+
+static char theme_syntax[] =
+    "{\"styles\": {\"title\": {\"bold\": true,}}}";
+
+void test_theme_files() {
+    printf("\n=== Testing Theme Files ===\n");
+    char* err = NULL;
+
+    uint64_t json_theme = LoadTheme(theme_json, THEME_FORMAT_JSON, &err);
+    printf("JSON theme loaded: %s\n", json_theme ? "yes" : "no");
+    char* title = StyleRender(ThemeGetStyle(json_theme, "title"), "Dashboard");
+    printf("%s\n", title);
+    FreeString(title);
+    char* warning = StyleRender(ThemeGetStyle(json_theme, "warning"), "Disk almost full");
+    printf("%s\n", warning);
+    FreeString(warning);
+    FreeTheme(json_theme);
+
+    uint64_t yaml_theme = LoadTheme(theme_yaml, THEME_FORMAT_AUTO, &err);
+    printf("YAML theme loaded: %s\n", yaml_theme ? "yes" : "no");
+    char* note = StyleRender(ThemeGetStyle(yaml_theme, "note"), "Remember");
+    printf("%s\n", note);
+    FreeString(note);
+    FreeTheme(yaml_theme);
+
+    uint64_t toml_theme = LoadTheme(theme_toml, THEME_FORMAT_AUTO, &err);
+    printf("TOML theme loaded: %s\n", toml_theme ? "yes" : "no");
+    char* label = StyleRender(ThemeGetStyle(toml_theme, "label"), "Label");
+    printf("%s\n", label);
+    FreeString(label);
+    FreeTheme(toml_theme);
+
+    char* errors[] = {theme_bad, theme_cycle, theme_syntax};
+    for (int i = 0; i < 3; i++) {
+        uint64_t failed = LoadTheme(errors[i], THEME_FORMAT_AUTO, &err);
+        printf("Invalid theme %d: id=%llu, error=%s\n", i, (unsigned long long)failed, err ? err : "(none)");
+        FreeString(err);
+        err = NULL;
+    }
+}
+
+void test_style_json() {
+    printf("\n=== Testing Style JSON ===\n");
+    uint64_t base_style = NewStyle();
+    uint64_t bold = StyleBold(base_style, 1);
+    uint64_t colored = StyleForeground(bold, "#FF5F87");
+    uint64_t padded = StylePadding(colored, 0, 2, 0, 2);
+    CBorder rounded = RoundedBorder();
+    uint64_t bordered = StyleBorder(padded, rounded);
+    FreeBorder(rounded);
+    uint64_t style = StyleBorderForeground(bordered, "63");
+
+    char* json = StyleToJSON(style);
+    printf("Serialized: %s\n", json);
+
+    char* err = NULL;
+    uint64_t restored = StyleFromJSON(json, &err);
+    char* again = StyleToJSON(restored);
+    printf("Round trip matches: %s\n", strcmp(json, again) == 0 ? "yes" : "no");
+
+    char* original = StyleRender(style, "Saved style");
+    char* loaded = StyleRender(restored, "Saved style");
+    printf("Renders identically: %s\n", strcmp(original, loaded) == 0 ? "yes" : "no");
+    printf("%s\n", loaded);
+
+    char bad[] = "{\"bold\": \"yes\"}";
+    uint64_t failed = StyleFromJSON(bad, &err);
+    printf("Invalid JSON style: id=%llu, error=%s\n", (unsigned long long)failed, err ? err : "(none)");
+
+    FreeString(err);
+    FreeString(loaded);
+    FreeString(original);
+    FreeString(again);
+    FreeString(json);
+    FreeStyle(restored);
+    FreeStyle(style);
+    FreeStyle(bordered);
+    FreeStyle(padded);
+    FreeStyle(colored);
+    FreeStyle(bold);
+    FreeStyle(base_style);
+}
+
+void test_style_css() {
+    printf("\n=== Testing CSS Styles ===\n");
+    char* err = NULL;
+    char css[] = "color: #ff0; background: navy; padding: 1 2; border: rounded; "
+                 "font-weight: bold; text-align: center; width: 20";
+    uint64_t style = StyleFromCSS(css, &err);
+    char* rendered = StyleRender(style, "From CSS");
+    printf("%s\n", rendered);
+    FreeString(rendered);
+
+    char* json = StyleToJSON(style);
+    printf("Properties: %s\n", json);
+    FreeString(json);
+    FreeStyle(style);
+
+    char* invalid[] = {"color: red; colr: blue", "padding: 1px", "font-weight", "color: #ggg"};
+    for (int i = 0; i < 4; i++) {
+        uint64_t failed = StyleFromCSS(invalid[i], &err);
+        printf("Invalid CSS %d: id=%llu, error=%s\n", i, (unsigned long long)failed, err ? err : "(none)");
+        FreeString(err);
+        err = NULL;
+    }
+}
+
+void test_html_export() {
+    printf("\n=== Testing HTML Export ===\n");
+    char sgr[] = "\x1b[1;38;2;255;95;135mBold pink\x1b[0m & <plain>\n"
+                 "\x1b[4;44m  underlined on blue  \x1b[0m \x1b[7mreversed\x1b[27m";
+    char* html = ANSIToHTML(sgr);
+    printf("%s\n", html);
+    FreeString(html);
+
+    SetColorProfile("truecolor");
+    uint64_t base_style = NewStyle();
+    uint64_t italic = StyleItalic(base_style, 1);
+    uint64_t styled = StyleForeground(italic, "#00FFFF");
+    char* rendered = StyleRender(styled, "from StyleRender");
+    char* rendered_html = ANSIToHTML(rendered);
+    printf("%s\n", rendered_html);
+    FreeString(rendered_html);
+    FreeString(rendered);
+    FreeStyle(styled);
+    FreeStyle(italic);
+    FreeStyle(base_style);
+    SetColorProfile("ascii");
+}
+
+void test_svg_export() {
+    printf("\n=== Testing SVG Export ===\n");
+    SetColorProfile("truecolor");
+    uint64_t base_style = NewStyle();
+    uint64_t bold = StyleBold(base_style, 1);
+    uint64_t colored = StyleForeground(bold, "#FF5F87");
+    CBorder rounded = RoundedBorder();
+    uint64_t boxed = StyleBorder(colored, rounded);
+    FreeBorder(rounded);
+    char* box = StyleRender(boxed, "Snapshot 你好");
+
+    CSVGOptions options = {0};
+    options.font_size = 16;
+    options.window_chrome = 1;
+    options.title = "lipgloss <demo>";
+    options.palette[1] = "#FF0000";
+    char* svg = RenderToSVG(box, &options);
+    printf("%s", svg);
+    FreeString(svg);
+
+    char* plain = RenderToSVG("plain text", NULL);
+    printf("%s", plain);
+    FreeString(plain);
+
+    FreeString(box);
+    FreeStyle(boxed);
+    FreeStyle(colored);
+    FreeStyle(bold);
+    FreeStyle(base_style);
+    SetColorProfile("ascii");
+}
+
+void test_ansi_utilities() {
+    printf("\n=== Testing ANSI Utilities ===\n");
+    char styled[] = "\x1b[1;31mHello\x1b[0m, \x1b[4mstyled\x1b[0m world";
+
+    char* stripped = Strip(styled);
+    printf("Strip: %s\n", stripped);
+    FreeString(stripped);
+
+    char* truncated = Truncate(styled, 10, "…");
+    char* truncated_plain = Strip(truncated);
+    printf("Truncate(10): %s (width %d)\n", truncated_plain, Width(truncated));
+    FreeString(truncated_plain);
+    FreeString(truncated);
+
+    char* left = TruncateLeft(styled, 7, "…");
+    char* left_plain = Strip(left);
+    printf("TruncateLeft(7): %s\n", left_plain);
+    FreeString(left_plain);
+    FreeString(left);
+
+    char* cut = Cut(styled, 3, 9);
+    char* cut_plain = Strip(cut);
+    printf("Cut(3, 9): %s\n", cut_plain);
+    FreeString(cut_plain);
+    FreeString(cut);
+
+    char* words = Wordwrap(styled, 8, "");
+    char* words_plain = Strip(words);
+    printf("Wordwrap(8):\n%s\n", words_plain);
+    FreeString(words_plain);
+    FreeString(words);
+
+    char* hard = Hardwrap(styled, 8, 0);
+    char* hard_plain = Strip(hard);
+    printf("Hardwrap(8):\n%s\n", hard_plain);
+    FreeString(hard_plain);
+    FreeString(hard);
+}
+
+// Replaces every digit with '*'. The buffer is malloc'd and freed by the library.
+static char* mask_digits(const char* content, void* userdata) {
+    char mask = *(const char*)userdata;
+    char* out = malloc(strlen(content) + 1);
+    if (out == NULL) {
+        return NULL;
+    }
+    for (size_t i = 0; ; i++) {
+        out[i] = (content[i] >= '0' && content[i] <= '9') ? mask : content[i];
+        if (content[i] == '\0') {
+            break;
+        }
+    }
+    return out;
+}
+
+static char* keep_content(const char* content, void* userdata) {
+    (void)content;
+    (void)userdata;
+    return NULL;
+}
+
+void test_style_transform() {
+    printf("\n=== Testing Style Transforms ===\n");
+    static char mask = '*';
+    uint64_t base_style = NewStyle();
+    uint64_t bold = StyleBold(base_style, 1);
+    uint64_t masked = StyleTransform(bold, mask_digits, &mask);
+    uint64_t padded = StylePaddingLeft(masked, 2);
+
+    char* secret = StyleRender(padded, "token=12345 pin=0042");
+    printf("Masked: %s\n", secret);
+    FreeString(secret);
+
+    uint64_t unmasked = StyleUnsetTransform(padded);
+    char* plain = StyleRender(unmasked, "token=12345 pin=0042");
+    printf("Unset: %s\n", plain);
+    FreeString(plain);
+
+    uint64_t passthrough = StyleTransform(base_style, keep_content, NULL);
+    char* unchanged = StyleRender(passthrough, "left as is 42");
+    printf("NULL result: %s\n", unchanged);
+    FreeString(unchanged);
+
+    FreeStyle(passthrough);
+    FreeStyle(unmasked);
+    FreeStyle(padded);
+    FreeStyle(masked);
+    FreeStyle(bold);
+    FreeStyle(base_style);
+}
+
+void test_text_properties() {
+    printf("\n=== Testing Text Properties ===\n");
+    uint64_t base_style = NewStyle();
+    uint64_t underlined = StyleUnderline(base_style, 1);
+    uint64_t no_space_underline = StyleUnderlineSpaces(underlined, 0);
+    uint64_t strikethrough = StyleStrikethrough(no_space_underline, 1);
+    uint64_t struck = StyleStrikethroughSpaces(strikethrough, 1);
+    uint64_t inline_style = StyleInline(struck, 1);
+    uint64_t tabbed = StyleTabWidth(inline_style, TAB_WIDTH_NO_CONVERSION);
+
+    printf("Bold: %d, Underline: %d, Strikethrough: %d\n",
+           StyleGetBold(tabbed), StyleGetUnderline(tabbed), StyleGetStrikethrough(tabbed));
+    printf("UnderlineSpaces: %d, StrikethroughSpaces: %d, ColorWhitespace: %d\n",
+           StyleGetUnderlineSpaces(tabbed), StyleGetStrikethroughSpaces(tabbed), StyleGetColorWhitespace(tabbed));
+    printf("Inline: %d, TabWidth: %d (unset %d)\n",
+           StyleGetInline(tabbed), StyleGetTabWidth(tabbed), StyleGetTabWidth(base_style));
+
+    static char hello[] = "hello";
+    static char world[] = "world";
+    char* parts[] = {hello, world};
+    uint64_t valued = StyleSetStrings(tabbed, parts, 2);
+    char* value = StyleGetValue(valued);
+    printf("SetStrings value: '%s'\n", value);
+    FreeString(value);
+
+    uint64_t no_underline = StyleUnsetUnderline(valued);
+    uint64_t not_inline = StyleUnsetInline(no_underline);
+    uint64_t default_tabs = StyleUnsetTabWidth(not_inline);
+    uint64_t cleared = StyleUnsetString(default_tabs);
+    value = StyleGetValue(cleared);
+    printf("After unset: Underline: %d, Inline: %d, TabWidth: %d, value: '%s'\n",
+           StyleGetUnderline(cleared), StyleGetInline(cleared), StyleGetTabWidth(cleared), value);
+    FreeString(value);
+
+    printf("Unknown style getter: %d\n", StyleGetBold(999999));
+
+    FreeStyle(cleared);
+    FreeStyle(default_tabs);
+    FreeStyle(not_inline);
+    FreeStyle(no_underline);
+    FreeStyle(valued);
+    FreeStyle(tabbed);
+    FreeStyle(inline_style);
+    FreeStyle(struck);
+    FreeStyle(strikethrough);
+    FreeStyle(no_space_underline);
+    FreeStyle(underlined);
+    FreeStyle(base_style);
+}
+
+void test_gradients() {
+    printf("\n=== Testing Gradients ===\n");
+    static char red[] = "#ff0000";
+    static char blue[] = "#00f";
+    static char green[] = "46";
+    char* stops[] = {red, blue, green};
+
+    char* mid = BlendColor(red, blue, 0.5);
+    printf("Blend midpoint: %s\n", mid);
+    FreeString(mid);
+
+    char* steps[5];
+    int written = BlendColors(stops, 3, 5, steps);
+    printf("Blended %d colors:", written);
+    for (int i = 0; i < written; i++) {
+        printf(" %s", steps[i]);
+        FreeString(steps[i]);
+    }
+    printf("\n");
+
+    SetColorProfile("truecolor");
+    char* header = RenderGradient("Gradient", stops, 2);
+    printf("Truecolor: %s\n", header);
+    FreeString(header);
+
+    SetColorProfile("ansi");
+    header = RenderGradient("Gradient", stops, 2);
+    printf("ANSI: %s\n", header);
+    FreeString(header);
+
+    SetColorProfile("ascii");
+    header = RenderGradient("Gradient", stops, 2);
+    printf("ASCII: %s\n", header);
+    FreeString(header);
+
+    static char bogus[] = "not-a-color";
+    char* invalid[] = {bogus};
+    printf("Invalid stop written: %d\n", BlendColors(invalid, 1, 3, steps));
+}
+
+void test_color_manipulation() {
+    printf("\n=== Testing Color Manipulation ===\n");
+    static char accent[] = "#3366cc";
+    static char white[] = "#ffffff";
+    static char black[] = "#000000";
+
+    char* lighter = ColorLighten(accent, 0.2);
+    char* darker = ColorDarken(accent, 0.2);
+    char* muted = ColorSaturate(accent, -0.5);
+    printf("Lighten: %s, Darken: %s, Desaturate: %s\n", lighter, darker, muted);
+
+    printf("Contrast black/white: %.2f\n", ColorContrastRatio(black, white));
+    printf("Contrast accent/white: %.2f\n", ColorContrastRatio(accent, white));
+
+    char* on_light = ColorReadableText(lighter, NULL, 0);
+    char* on_dark = ColorReadableText(darker, NULL, 0);
+    printf("Readable on %s: %s, on %s: %s\n", lighter, on_light, darker, on_dark);
+
+    char* candidates[] = {accent, darker};
+    char* picked = ColorReadableText(white, candidates, 2);
+    printf("Best candidate on white: %s\n", picked);
+
+    printf("ANSI256: %d, ANSI16: %d\n", ColorToANSI256(accent), ColorToANSI16(accent));
+    printf("ANSI index 196 to ANSI16: %d\n", ColorToANSI16("196"));
+    printf("Invalid color: %d\n", ColorToANSI256("nope"));
+
+    FreeString(picked);
+    FreeString(on_dark);
+    FreeString(on_light);
+    FreeString(muted);
+    FreeString(darker);
+    FreeString(lighter);
+}
+
+void test_handles() {
+    printf("\n=== Testing Handles ===\n");
+    uint64_t style = NewStyle();
+    uint64_t table = NewTable();
+    printf("Kinds: style=%d table=%d bogus=%d\n", HandleKind(style), HandleKind(table), HandleKind(42));
+
+    char* err = HandleError(table);
+    printf("Live table error: '%s'\n", err);
+    FreeString(err);
+
+    char* rendered = StyleRender(table, "wrong kind");
+    printf("Render with table handle: '%s'\n", rendered);
+    FreeString(rendered);
+
+    FreeStyle(style);
+    printf("Freed style valid: %d\n", HandleIsValid(style));
+    uint64_t reused = NewStyle();
+    printf("Reused slot gets new handle: %d\n", reused != style);
+    err = HandleError(style);
+    printf("Stale handle: %s\n", strstr(err, "use of freed style handle") ? "use of freed style handle" : err);
+    FreeString(err);
+    printf("Bold on stale handle: %llu\n", (unsigned long long)StyleBold(style, 1));
+
+    FreeStyle(reused);
+    FreeTable(table);
+}
+
+void test_render_into() {
+    printf("\n=== Testing Render Into Buffers ===\n");
+    char small[4];
+    char buf[256];
+    size_t needed = 0;
+    uint64_t base = NewStyle();
+    uint64_t style = StylePadding(base, 0, 1, 0, 1);
+
+    int status = StyleRenderInto(style, "Buffered", small, sizeof(small), &needed);
+    printf("Too small: status=%d needed=%zu\n", status, needed);
+    status = StyleRenderInto(style, "Buffered", buf, needed, &needed);
+    printf("Exact fit: status=%d '%s'\n", status, buf);
+
+    status = JoinHorizontalInto(0.5, "Left", "Right", buf, sizeof(buf), &needed);
+    printf("JoinHorizontalInto: status=%d needed=%zu '%s'\n", status, needed, buf);
+
+    status = PlaceInto(7, 1, 0.5, 0.5, "mid", buf, sizeof(buf), NULL);
+    printf("PlaceInto: status=%d '%s'\n", status, buf);
+
+    uint64_t table = NewTable();
+    char h1[] = "A";
+    char h2[] = "B";
+    char* headers[] = {h1, h2};
+    TableAddHeaders(table, headers, 2);
+    status = RenderTableInto(table, NULL, 0, &needed);
+    printf("RenderTableInto size query: status=%d needed=%zu\n", status, needed);
+    status = RenderTableInto(table, buf, sizeof(buf), &needed);
+    printf("RenderTableInto: status=%d\n%s\n", status, buf);
+
+    status = RenderTableInto(style, buf, sizeof(buf), &needed);
+    printf("Wrong handle: status=%d needed=%zu buf='%s'\n", status, needed, buf);
+
+    FreeTable(table);
+    FreeStyle(style);
+    FreeStyle(base);
+}
+
+void test_style_render_batch() {
+    printf("\n=== Testing Batch Rendering ===\n");
+    uint64_t base = NewStyle();
+    uint64_t bold = StyleBold(base, 1);
+    uint64_t italic = StyleItalic(base, 1);
+    const uint64_t styles[] = {bold, italic, 0, bold};
+    const char* texts[] = {"one", "two", "three", "four"};
+    char* out[4];
+
+    size_t rendered = StyleRenderBatch(styles, texts, 4, out);
+    printf("Rendered %zu of 4\n", rendered);
+    for (int i = 0; i < 4; i++) {
+        printf("  [%d] '%s'\n", i, out[i]);
+        FreeString(out[i]);
+    }
+
+    FreeStyle(bold);
+    FreeStyle(italic);
+    FreeStyle(base);
+}
+
+void test_render_cache() {
+    printf("\n=== Testing Render Cache ===\n");
+    SetRenderCache(2, 0);
+    uint64_t base = NewStyle();
+    uint64_t style = StylePadding(base, 0, 1, 0, 1);
+
+    for (int i = 0; i < 3; i++) {
+        char* status = StyleRender(style, "Status: OK");
+        printf("Frame %d: '%s'\n", i, status);
+        FreeString(status);
+    }
+    char* stats = GetRenderCacheStats();
+    printf("%s\n", stats);
+    FreeString(stats);
+
+    const char* texts[] = {"a", "b", "c"};
+    for (int i = 0; i < 3; i++) {
+        FreeString(StyleRender(base, (char*)texts[i]));
+    }
+    stats = GetRenderCacheStats();
+    printf("After overflow: %s\n", stats);
+    FreeString(stats);
+
+    FreeStyle(base);
+    stats = GetRenderCacheStats();
+    printf("After free: %s\n", stats);
+    FreeString(stats);
+
+    SetRenderCache(0, 0);
+    stats = GetRenderCacheStats();
+    printf("%s\n", stats);
+    FreeString(stats);
+    FreeStyle(style);
+}
+
 int main() {
     test_basic_utilities();
     test_text_formatting();
@@ -462,8 +1276,27 @@ int main() {
     test_table_borders();
     test_list();
     test_list_enumerators();
+    test_list_styles();
+    test_nested_lists();
     test_tree();
     test_tree_enumerators();
+    test_tree_mutation();
+    test_tree_custom_enumerators();
+    test_markdown();
+    test_theme_files();
+    test_style_json();
+    test_style_css();
+    test_html_export();
+    test_svg_export();
+    test_ansi_utilities();
+    test_style_transform();
+    test_text_properties();
+    test_gradients();
+    test_color_manipulation();
+    test_handles();
+    test_render_into();
+    test_style_render_batch();
+    test_render_cache();
     
     printf("\n=== All tests completed ===\n");
     return 0;
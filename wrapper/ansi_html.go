@@ -0,0 +1,106 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"html"
+	"strings"
+	"unsafe"
+)
+
+// Colors used for text drawn with the terminal's default colors. They only
+// matter where the default has to be spelled out, i.e. for reverse video,
+// and on the enclosing <pre>.
+const (
+	htmlDefaultForeground = "#c0c0c0"
+	htmlDefaultBackground = "#000000"
+)
+
+// css returns the inline style for a run, or "" if it has no attributes
+func (s sgrState) css(p *ansiPalette) string {
+	fg, bg := p.hex(s.fg), p.hex(s.bg)
+	if s.reverse {
+		if fg == "" {
+			fg = htmlDefaultForeground
+		}
+		if bg == "" {
+			bg = htmlDefaultBackground
+		}
+		fg, bg = bg, fg
+	}
+
+	var decls []string
+	if s.conceal {
+		decls = append(decls, "visibility:hidden")
+	}
+	if fg != "" {
+		decls = append(decls, "color:"+fg)
+	}
+	if bg != "" {
+		decls = append(decls, "background-color:"+bg)
+	}
+	if s.bold {
+		decls = append(decls, "font-weight:bold")
+	}
+	if s.faint {
+		decls = append(decls, "opacity:0.6")
+	}
+	if s.italic {
+		decls = append(decls, "font-style:italic")
+	}
+
+	var decorations []string
+	if s.underline {
+		decorations = append(decorations, "underline")
+	}
+	if s.strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if s.blink {
+		decorations = append(decorations, "blink")
+	}
+	if len(decorations) > 0 {
+		decls = append(decls, "text-decoration:"+strings.Join(decorations, " "))
+	}
+	return strings.Join(decls, ";")
+}
+
+// ansiToHTML converts SGR-styled text into a <pre> block of <span> runs.
+// Whitespace is kept verbatim by the <pre>, so box drawing and alignment
+// line up as they do in the terminal.
+func ansiToHTML(text string) string {
+	var b strings.Builder
+	b.WriteString(`<pre style="font-family:monospace;white-space:pre;line-height:1.2;color:` +
+		htmlDefaultForeground + `;background-color:` + htmlDefaultBackground + `">`)
+	for _, run := range parseSGR(text) {
+		escaped := html.EscapeString(run.text)
+		css := run.state.css(&defaultANSIPalette)
+		if css == "" {
+			b.WriteString(escaped)
+			continue
+		}
+		b.WriteString(`<span style="` + css + `">` + escaped + `</span>`)
+	}
+	b.WriteString("</pre>")
+	return b.String()
+}
+
+// ANSIToHTML converts text styled with ANSI SGR sequences, such as the
+// output of StyleRender or RenderTable, into HTML.
+//
+//export ANSIToHTML
+func ANSIToHTML(text *C.char) (ret *C.char) {
+	defer recoverString("ANSIToHTML", &ret, "")
+	result := ansiToHTML(String.GoString(text))
+	cs, err := String.CString(result)
+	if err != nil {
+		Log(LogLevelError, "ANSIToHTML memory allocation error: %v", err)
+		return Memory.CString("", "ANSIToHTML result")
+	}
+	Memory.Track(unsafe.Pointer(cs), "ANSIToHTML result")
+	return cs
+}
@@ -0,0 +1,23 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestANSIToHTML(t *testing.T) {
+	got := takeString(ANSIToHTML(cString(t, "\x1b[1;31m<b>&\x1b[0m plain")))
+	for _, want := range []string{"&lt;b&gt;&amp;", "font-weight:bold", " plain"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("ANSIToHTML = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "\x1b") {
+		t.Errorf("ANSIToHTML left escape codes in %q", got)
+	}
+	if got := takeString(ANSIToHTML(nil)); strings.Contains(got, "\x1b") {
+		t.Errorf("ANSIToHTML(NULL) = %q", got)
+	}
+}
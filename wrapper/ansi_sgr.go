@@ -0,0 +1,264 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sgrColorKind tells how an sgrColor was specified
+type sgrColorKind int
+
+const (
+	sgrColorDefault sgrColorKind = iota
+	sgrColorIndexed
+	sgrColorRGB
+)
+
+// sgrColor is a color selected by an SGR sequence
+type sgrColor struct {
+	kind    sgrColorKind
+	index   int
+	r, g, b uint8
+}
+
+// sgrState is the set of graphic attributes active at a point in the text
+type sgrState struct {
+	fg, bg        sgrColor
+	bold, faint   bool
+	italic        bool
+	underline     bool
+	blink         bool
+	reverse       bool
+	conceal       bool
+	strikethrough bool
+}
+
+// sgrRun is a piece of text drawn with a single set of attributes
+type sgrRun struct {
+	text  string
+	state sgrState
+}
+
+// ansiPalette holds the 16 base colors as hex strings. Indexes 16-255 are
+// the fixed xterm color cube and grayscale ramp.
+type ansiPalette [16]string
+
+// defaultANSIPalette matches the xterm defaults termenv uses
+var defaultANSIPalette = ansiPalette{
+	"#000000", "#800000", "#008000", "#808000", "#000080", "#800080", "#008080", "#c0c0c0",
+	"#808080", "#ff0000", "#00ff00", "#ffff00", "#0000ff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+// hex resolves a color against the palette, returning "" for the default
+func (p *ansiPalette) hex(c sgrColor) string {
+	switch c.kind {
+	case sgrColorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.r, c.g, c.b)
+	case sgrColorIndexed:
+		switch {
+		case c.index < 16:
+			return p[c.index]
+		case c.index < 232:
+			levels := [6]int{0, 95, 135, 175, 215, 255}
+			i := c.index - 16
+			return fmt.Sprintf("#%02x%02x%02x", levels[i/36], levels[i/6%6], levels[i%6])
+		default:
+			v := 8 + 10*(c.index-232)
+			return fmt.Sprintf("#%02x%02x%02x", v, v, v)
+		}
+	}
+	return ""
+}
+
+// parseSGRColor reads an extended color (38/48) starting at params[i] and
+// returns the number of parameters consumed. Both the ';' form
+// (38;5;n, 38;2;r;g;b) and the ':' form (38:5:n, 38:2::r:g:b) are accepted.
+func parseSGRColor(params [][]int, i int) (sgrColor, int) {
+	sub := params[i]
+	if len(sub) > 1 {
+		switch {
+		case sub[1] == 5 && len(sub) >= 3:
+			return sgrColor{kind: sgrColorIndexed, index: clampByte(sub[2])}, 1
+		case sub[1] == 2 && len(sub) >= 5:
+			rgb := sub[len(sub)-3:]
+			return sgrColor{kind: sgrColorRGB, r: uint8(clampByte(rgb[0])), g: uint8(clampByte(rgb[1])), b: uint8(clampByte(rgb[2]))}, 1
+		}
+		return sgrColor{}, 1
+	}
+
+	if i+1 >= len(params) {
+		return sgrColor{}, 1
+	}
+	switch params[i+1][0] {
+	case 5:
+		if i+2 < len(params) {
+			return sgrColor{kind: sgrColorIndexed, index: clampByte(params[i+2][0])}, 3
+		}
+	case 2:
+		if i+4 < len(params) {
+			return sgrColor{
+				kind: sgrColorRGB,
+				r:    uint8(clampByte(params[i+2][0])),
+				g:    uint8(clampByte(params[i+3][0])),
+				b:    uint8(clampByte(params[i+4][0])),
+			}, 5
+		}
+	}
+	return sgrColor{}, len(params) - i
+}
+
+func clampByte(n int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > 255 {
+		return 255
+	}
+	return n
+}
+
+// apply updates the state with the parameters of one SGR sequence
+func (s *sgrState) apply(raw string) {
+	if raw == "" {
+		*s = sgrState{}
+		return
+	}
+
+	var params [][]int
+	for _, p := range strings.Split(raw, ";") {
+		var sub []int
+		for _, v := range strings.Split(p, ":") {
+			n, _ := strconv.Atoi(v)
+			sub = append(sub, n)
+		}
+		params = append(params, sub)
+	}
+
+	for i := 0; i < len(params); i++ {
+		code := params[i][0]
+		switch {
+		case code == 0:
+			*s = sgrState{}
+		case code == 1:
+			s.bold = true
+		case code == 2:
+			s.faint = true
+		case code == 3:
+			s.italic = true
+		case code == 4:
+			s.underline = len(params[i]) == 1 || params[i][1] != 0
+		case code == 5 || code == 6:
+			s.blink = true
+		case code == 7:
+			s.reverse = true
+		case code == 8:
+			s.conceal = true
+		case code == 9:
+			s.strikethrough = true
+		case code == 21:
+			s.underline = true
+		case code == 22:
+			s.bold, s.faint = false, false
+		case code == 23:
+			s.italic = false
+		case code == 24:
+			s.underline = false
+		case code == 25:
+			s.blink = false
+		case code == 27:
+			s.reverse = false
+		case code == 28:
+			s.conceal = false
+		case code == 29:
+			s.strikethrough = false
+		case code >= 30 && code <= 37:
+			s.fg = sgrColor{kind: sgrColorIndexed, index: code - 30}
+		case code == 38:
+			c, n := parseSGRColor(params, i)
+			s.fg = c
+			i += n - 1
+		case code == 39:
+			s.fg = sgrColor{}
+		case code >= 40 && code <= 47:
+			s.bg = sgrColor{kind: sgrColorIndexed, index: code - 40}
+		case code == 48:
+			c, n := parseSGRColor(params, i)
+			s.bg = c
+			i += n - 1
+		case code == 49:
+			s.bg = sgrColor{}
+		case code >= 90 && code <= 97:
+			s.fg = sgrColor{kind: sgrColorIndexed, index: code - 90 + 8}
+		case code >= 100 && code <= 107:
+			s.bg = sgrColor{kind: sgrColorIndexed, index: code - 100 + 8}
+		}
+	}
+}
+
+// escapeSequenceEnd returns the index just past the escape sequence that
+// starts at text[i]. Unterminated sequences run to the end of text.
+func escapeSequenceEnd(text string, i int) int {
+	if i+1 >= len(text) {
+		return len(text)
+	}
+	switch text[i+1] {
+	case '[':
+		// CSI: parameter and intermediate bytes up to a final byte
+		for j := i + 2; j < len(text); j++ {
+			if text[j] >= 0x40 && text[j] <= 0x7e {
+				return j + 1
+			}
+		}
+		return len(text)
+	case ']', 'P', '_', '^':
+		// OSC, DCS, APC and PM strings end with BEL or ST
+		for j := i + 2; j < len(text); j++ {
+			if text[j] == '\a' {
+				return j + 1
+			}
+			if text[j] == '\x1b' && j+1 < len(text) && text[j+1] == '\\' {
+				return j + 2
+			}
+		}
+		return len(text)
+	}
+	return i + 2
+}
+
+// parseSGR splits text into runs of uniformly styled text. SGR sequences
+// update the style; every other escape sequence (cursor movement, OSC
+// hyperlinks and titles, ...) is dropped.
+func parseSGR(text string) []sgrRun {
+	var runs []sgrRun
+	var state sgrState
+	var buf strings.Builder
+
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		if n := len(runs); n > 0 && runs[n-1].state == state {
+			runs[n-1].text += buf.String()
+		} else {
+			runs = append(runs, sgrRun{text: buf.String(), state: state})
+		}
+		buf.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		if text[i] != '\x1b' {
+			buf.WriteByte(text[i])
+			i++
+			continue
+		}
+		end := escapeSequenceEnd(text, i)
+		if end-i > 2 && text[i+1] == '[' && text[end-1] == 'm' {
+			flush()
+			state.apply(text[i+2 : end-1])
+		}
+		i = end
+	}
+	flush()
+	return runs
+}
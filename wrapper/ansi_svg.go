@@ -0,0 +1,309 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/rivo/uniseg"
+)
+
+// svgOptions is the Go side of CSVGOptions with defaults applied
+type svgOptions struct {
+	fontSize   float64
+	fontFamily string
+	foreground string
+	background string
+	palette    ansiPalette
+	padding    float64
+	chrome     bool
+	title      string
+}
+
+const (
+	svgCellWidthRatio  = 0.6
+	svgLineHeightRatio = 1.2
+	svgChromeHeight    = 36
+)
+
+func defaultSVGOptions() svgOptions {
+	return svgOptions{
+		fontSize:   14,
+		fontFamily: "ui-monospace, SFMono-Regular, Menlo, Consolas, 'DejaVu Sans Mono', monospace",
+		foreground: htmlDefaultForeground,
+		background: htmlDefaultBackground,
+		palette:    defaultANSIPalette,
+		padding:    16,
+	}
+}
+
+// svgColor validates a user supplied #rgb or #rrggbb color, keeping
+// fallback if it is unset or invalid
+func svgColor(c *C.char, fallback, name string) string {
+	if c == nil {
+		return fallback
+	}
+	color := C.GoString(c)
+	valid := Validate.Color(color, "svg-"+name) == nil && color[0] == '#'
+	if valid {
+		_, err := strconv.ParseUint(color[1:], 16, 32)
+		valid = err == nil
+	}
+	if !valid {
+		Log(LogLevelError, "RenderToSVG: invalid %s color %q, expected #rgb or #rrggbb", name, color)
+		return fallback
+	}
+	return color
+}
+
+func svgOptionsFromC(opts *C.CSVGOptions) svgOptions {
+	o := defaultSVGOptions()
+	if opts == nil {
+		return o
+	}
+	if opts.font_size > 0 {
+		o.fontSize = float64(opts.font_size)
+	}
+	if opts.font_family != nil {
+		o.fontFamily = C.GoString(opts.font_family)
+	}
+	o.foreground = svgColor(opts.foreground, o.foreground, "foreground")
+	o.background = svgColor(opts.background, o.background, "background")
+	for i := range o.palette {
+		o.palette[i] = svgColor(opts.palette[i], o.palette[i], fmt.Sprintf("palette[%d]", i))
+	}
+	if opts.padding > 0 {
+		o.padding = float64(opts.padding)
+	} else if opts.padding < 0 {
+		o.padding = 0
+	}
+	o.chrome = opts.window_chrome != 0
+	if opts.title != nil {
+		o.title = C.GoString(opts.title)
+	}
+	return o
+}
+
+func svgNum(f float64) string {
+	s := strconv.FormatFloat(f, 'f', 2, 64)
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+// svgCell is a grapheme cluster placed on the grid
+type svgCell struct {
+	text  string
+	col   int
+	width int
+	state sgrState
+}
+
+// layoutSVGCells places every grapheme cluster of text on a grid of
+// terminal cells, measuring clusters the way lipgloss.Width does
+func layoutSVGCells(text string) (rows [][]svgCell, cols int) {
+	rows = [][]svgCell{nil}
+	col := 0
+	for _, run := range parseSGR(text) {
+		rest := run.text
+		state := -1
+		for len(rest) > 0 {
+			var cluster string
+			var width int
+			cluster, rest, width, state = uniseg.FirstGraphemeClusterInString(rest, state)
+			if cluster == "\n" || cluster == "\r\n" {
+				rows = append(rows, nil)
+				col = 0
+				continue
+			}
+			if cluster == "\r" {
+				continue
+			}
+			row := len(rows) - 1
+			rows[row] = append(rows[row], svgCell{text: cluster, col: col, width: width, state: run.state})
+			col += width
+			if col > cols {
+				cols = col
+			}
+		}
+	}
+	if len(rows) > 1 && len(rows[len(rows)-1]) == 0 {
+		rows = rows[:len(rows)-1]
+	}
+	return rows, cols
+}
+
+// colors resolves the fill and background of a cell, applying reverse video
+func (o *svgOptions) colors(s sgrState) (fg, bg string) {
+	fg, bg = o.palette.hex(s.fg), o.palette.hex(s.bg)
+	if s.reverse {
+		if fg == "" {
+			fg = o.foreground
+		}
+		if bg == "" {
+			bg = o.background
+		}
+		fg, bg = bg, fg
+	}
+	if fg == "" {
+		fg = o.foreground
+	}
+	return fg, bg
+}
+
+// textAttrs returns the SVG presentation attributes for a run of text
+func (o *svgOptions) textAttrs(s sgrState, fg string) string {
+	attrs := []string{`fill="` + html.EscapeString(fg) + `"`}
+	if s.bold {
+		attrs = append(attrs, `font-weight="bold"`)
+	}
+	if s.italic {
+		attrs = append(attrs, `font-style="italic"`)
+	}
+	if s.faint {
+		attrs = append(attrs, `opacity="0.6"`)
+	}
+	var decorations []string
+	if s.underline {
+		decorations = append(decorations, "underline")
+	}
+	if s.strikethrough {
+		decorations = append(decorations, "line-through")
+	}
+	if len(decorations) > 0 {
+		attrs = append(attrs, `text-decoration="`+strings.Join(decorations, " ")+`"`)
+	}
+	return strings.Join(attrs, " ")
+}
+
+// renderSVG draws SGR-styled text as a standalone SVG document. Every
+// grapheme cluster gets an explicit x position derived from its cell column,
+// so wide glyphs and box drawing line up regardless of the font's metrics.
+func renderSVG(text string, o svgOptions) string {
+	rows, cols := layoutSVGCells(text)
+	cellWidth := o.fontSize * svgCellWidthRatio
+	lineHeight := o.fontSize * svgLineHeightRatio
+
+	top := o.padding
+	if o.chrome {
+		top += svgChromeHeight
+	}
+	width := float64(cols)*cellWidth + 2*o.padding
+	height := float64(len(rows))*lineHeight + top + o.padding
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNum(width), svgNum(height), svgNum(width), svgNum(height))
+
+	if o.chrome {
+		fmt.Fprintf(&b, `<rect width="%s" height="%s" rx="8" ry="8" fill="%s"/>`+"\n", svgNum(width), svgNum(height), html.EscapeString(o.background))
+		for i, color := range []string{"#ff5f56", "#ffbd2e", "#27c93f"} {
+			fmt.Fprintf(&b, `<circle cx="%d" cy="18" r="6" fill="%s"/>`+"\n", 20+i*20, color)
+		}
+		if o.title != "" {
+			fmt.Fprintf(&b, `<text x="%s" y="22" text-anchor="middle" font-family="%s" font-size="13" fill="%s" opacity="0.7">%s</text>`+"\n",
+				svgNum(width/2), html.EscapeString(o.fontFamily), html.EscapeString(o.foreground), html.EscapeString(o.title))
+		}
+	} else {
+		fmt.Fprintf(&b, `<rect width="%s" height="%s" fill="%s"/>`+"\n", svgNum(width), svgNum(height), html.EscapeString(o.background))
+	}
+
+	fmt.Fprintf(&b, `<g font-family="%s" font-size="%s" xml:space="preserve">`+"\n", html.EscapeString(o.fontFamily), svgNum(o.fontSize))
+
+	for r, row := range rows {
+		y := top + float64(r)*lineHeight
+
+		// Backgrounds first so glyphs that overhang their cell stay visible.
+		// Adjacent cells sharing a background are merged into one rect.
+		for i := 0; i < len(row); {
+			_, bg := o.colors(row[i].state)
+			start, end := row[i].col, row[i].col+row[i].width
+			j := i + 1
+			for ; j < len(row); j++ {
+				if _, next := o.colors(row[j].state); next != bg || row[j].col != end {
+					break
+				}
+				end += row[j].width
+			}
+			if bg != "" && end > start {
+				fmt.Fprintf(&b, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+					svgNum(o.padding+float64(start)*cellWidth), svgNum(y),
+					svgNum(float64(end-start)*cellWidth), svgNum(lineHeight), html.EscapeString(bg))
+			}
+			i = j
+		}
+
+		baseline := svgNum(y + lineHeight*0.8)
+		for i := 0; i < len(row); {
+			state := row[i].state
+			j := i
+			for j < len(row) && row[j].state == state {
+				j++
+			}
+			if state.conceal {
+				i = j
+				continue
+			}
+
+			// One x per code point: clusters made of a single rune share a
+			// tspan, multi-rune clusters get their own
+			var spans []string
+			var xs []string
+			var chars strings.Builder
+			flush := func() {
+				if chars.Len() > 0 {
+					spans = append(spans, `<tspan x="`+strings.Join(xs, " ")+`">`+html.EscapeString(chars.String())+`</tspan>`)
+				}
+				xs = xs[:0]
+				chars.Reset()
+			}
+			for _, cell := range row[i:j] {
+				x := svgNum(o.padding + float64(cell.col)*cellWidth)
+				if cell.text == " " {
+					continue
+				}
+				if utf8.RuneCountInString(cell.text) > 1 {
+					flush()
+					spans = append(spans, `<tspan x="`+x+`">`+html.EscapeString(cell.text)+`</tspan>`)
+					continue
+				}
+				xs = append(xs, x)
+				chars.WriteString(cell.text)
+			}
+			flush()
+
+			if len(spans) > 0 {
+				fg, _ := o.colors(state)
+				fmt.Fprintf(&b, `<text y="%s" %s>%s</text>`+"\n", baseline, o.textAttrs(state, fg), strings.Join(spans, ""))
+			}
+			i = j
+		}
+	}
+
+	b.WriteString("</g>\n</svg>\n")
+	return b.String()
+}
+
+// RenderToSVG draws text styled with ANSI SGR sequences, such as the output
+// of StyleRender, JoinHorizontal or Place, as a standalone SVG image. Pass
+// NULL options to use the defaults.
+//
+//export RenderToSVG
+func RenderToSVG(ansiText *C.char, options *C.CSVGOptions) (ret *C.char) {
+	defer recoverString("RenderToSVG", &ret, "")
+	result := renderSVG(String.GoString(ansiText), svgOptionsFromC(options))
+	cs, err := String.CString(result)
+	if err != nil {
+		Log(LogLevelError, "RenderToSVG memory allocation error: %v", err)
+		return Memory.CString("", "RenderToSVG result")
+	}
+	Memory.Track(unsafe.Pointer(cs), "RenderToSVG result")
+	return cs
+}
@@ -0,0 +1,36 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// styledHello is a short line with two styled spans
+const styledHello = "\x1b[1mHello\x1b[0m, \x1b[31mWorld\x1b[0m"
+
+func TestRenderToSVG(t *testing.T) {
+	golden(t, "svg_window", takeString(RenderToSVG(cString(t, styledHello), cSVGOptions(t, "demo"))))
+
+	plain := takeString(RenderToSVG(cString(t, "plain"), nil))
+	if !strings.HasPrefix(plain, "<svg") || !strings.Contains(plain, "plain") {
+		t.Errorf("RenderToSVG with default options = %q", plain)
+	}
+}
+
+func TestSVGColor(t *testing.T) {
+	for _, color := range []string{"#0af", "#00aaff"} {
+		if got := svgColor(cString(t, color), "#000000", "background"); got != color {
+			t.Errorf("svgColor(%q) = %q", color, got)
+		}
+	}
+	// Only hex digits are accepted, so nothing can escape the fill attribute
+	for _, color := range []string{`#"/><ab`, "#ggg", "00aaff"} {
+		expectLog(t, "invalid background color", func() {
+			if got := svgColor(cString(t, color), "#000000", "background"); got != "#000000" {
+				t.Errorf("svgColor(%q) = %q, want the fallback", color, got)
+			}
+		})
+	}
+}
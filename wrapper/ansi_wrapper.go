@@ -0,0 +1,155 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"strings"
+	"unsafe"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/rivo/uniseg"
+)
+
+// truncateLeft removes the first n cells of s, keeping every escape
+// sequence so styles that start in the removed part still apply to the
+// rest. A wide character straddling the cut is dropped. prefix is inserted
+// where text was removed.
+func truncateLeft(s string, n int, prefix string) string {
+	if n <= 0 {
+		return s
+	}
+
+	var b strings.Builder
+	col := 0
+	state := -1
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			end := escapeSequenceEnd(s, i)
+			b.WriteString(s[i:end])
+			i = end
+			continue
+		}
+
+		// Measure up to the next escape sequence one cluster at a time
+		end := strings.IndexByte(s[i:], '\x1b')
+		if end < 0 {
+			end = len(s)
+		} else {
+			end += i
+		}
+		for i < end {
+			if col >= n {
+				b.WriteString(s[i:end])
+				i = end
+				break
+			}
+			var cluster string
+			var width int
+			cluster, _, width, state = uniseg.FirstGraphemeClusterInString(s[i:end], state)
+			i += len(cluster)
+			col += width
+			if col >= n {
+				b.WriteString(prefix)
+				prefix = ""
+			}
+		}
+	}
+	return b.String()
+}
+
+// ansiResult copies result into a tracked C string
+func ansiResult(result, op string) *C.char {
+	cs, err := String.CString(result)
+	if err != nil {
+		Log(LogLevelError, "%s memory allocation error: %v", op, err)
+		defaultCs := Memory.CString("", op+" result")
+		return defaultCs
+	}
+	Memory.Track(unsafe.Pointer(cs), op+" result")
+	return cs
+}
+
+// Strip removes all ANSI escape sequences from str.
+//
+//export Strip
+func Strip(str *C.char) (ret *C.char) {
+	defer recoverString("Strip", &ret, "")
+	return ansiResult(ansi.Strip(String.GoString(str)), "Strip")
+}
+
+// Truncate shortens str to at most length cells, including tail, without
+// breaking escape sequences.
+//
+//export Truncate
+func Truncate(str *C.char, length C.int, tail *C.char) (ret *C.char) {
+	defer recoverString("Truncate", &ret, "")
+	if err := Validate.Dimension(int(length), "length"); err != nil {
+		Log(LogLevelError, "Truncate validation error: %v", err)
+		defaultCs := Memory.CString("", "Truncate result")
+		return defaultCs
+	}
+	return ansiResult(ansi.Truncate(String.GoString(str), int(length), String.GoString(tail)), "Truncate")
+}
+
+// TruncateLeft removes the first n cells of str and puts prefix in their
+// place, without breaking escape sequences.
+//
+//export TruncateLeft
+func TruncateLeft(str *C.char, n C.int, prefix *C.char) (ret *C.char) {
+	defer recoverString("TruncateLeft", &ret, "")
+	if err := Validate.Dimension(int(n), "n"); err != nil {
+		Log(LogLevelError, "TruncateLeft validation error: %v", err)
+		defaultCs := Memory.CString("", "TruncateLeft result")
+		return defaultCs
+	}
+	return ansiResult(truncateLeft(String.GoString(str), int(n), String.GoString(prefix)), "TruncateLeft")
+}
+
+// Wordwrap wraps str at word boundaries so no line exceeds limit cells.
+// Words longer than limit are left intact. breakpoints lists extra
+// characters, besides spaces, after which a line may break.
+//
+//export Wordwrap
+func Wordwrap(str *C.char, limit C.int, breakpoints *C.char) (ret *C.char) {
+	defer recoverString("Wordwrap", &ret, "")
+	if err := Validate.Dimension(int(limit), "limit"); err != nil {
+		Log(LogLevelError, "Wordwrap validation error: %v", err)
+		defaultCs := Memory.CString("", "Wordwrap result")
+		return defaultCs
+	}
+	return ansiResult(ansi.Wordwrap(String.GoString(str), int(limit), String.GoString(breakpoints)), "Wordwrap")
+}
+
+// Hardwrap breaks str every limit cells regardless of word boundaries.
+// Spaces at the start of wrapped lines are dropped unless preserveSpace is
+// nonzero.
+//
+//export Hardwrap
+func Hardwrap(str *C.char, limit C.int, preserveSpace C.int) (ret *C.char) {
+	defer recoverString("Hardwrap", &ret, "")
+	if err := Validate.Dimension(int(limit), "limit"); err != nil {
+		Log(LogLevelError, "Hardwrap validation error: %v", err)
+		defaultCs := Memory.CString("", "Hardwrap result")
+		return defaultCs
+	}
+	return ansiResult(ansi.Hardwrap(String.GoString(str), int(limit), String.ToBool(preserveSpace)), "Hardwrap")
+}
+
+// Cut returns the cells of str in the column range [left, right), keeping
+// the escape sequences needed to style them.
+//
+//export Cut
+func Cut(str *C.char, left, right C.int) (ret *C.char) {
+	defer recoverString("Cut", &ret, "")
+	if left < 0 || right < left {
+		Log(LogLevelError, "Cut validation error: invalid range [%d, %d)", int(left), int(right))
+		defaultCs := Memory.CString("", "Cut result")
+		return defaultCs
+	}
+	truncated := ansi.Truncate(String.GoString(str), int(right), "")
+	return ansiResult(truncateLeft(truncated, int(left), ""), "Cut")
+}
@@ -0,0 +1,48 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import "testing"
+
+func TestANSIUtilities(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		call func() *cChar
+		want string
+	}{
+		{"Strip", func() *cChar { return Strip(cString(t, styledHello)) }, "Hello, World"},
+		{"Truncate", func() *cChar { return Truncate(cString(t, styledHello), 6, cString(t, "…")) },
+			"\x1b[1mHello\x1b[0m…\x1b[31m\x1b[0m"},
+		{"TruncateLeft", func() *cChar { return TruncateLeft(cString(t, styledHello), 7, cString(t, "…")) },
+			"\x1b[1m\x1b[0m…\x1b[31mWorld\x1b[0m"},
+		{"Wordwrap", func() *cChar { return Wordwrap(cString(t, "one two-three"), 6, cString(t, "-")) },
+			"one\ntwo-\nthree"},
+		{"Hardwrap", func() *cChar { return Hardwrap(cString(t, "abcdef gh"), 4, 0) }, "abcd\nef g\nh"},
+		{"Cut", func() *cChar { return Cut(cString(t, styledHello), 2, 9) }, "\x1b[1mllo\x1b[0m, \x1b[31mWo\x1b[0m"},
+		{"Strip(NULL)", func() *cChar { return Strip(nil) }, ""},
+	} {
+		if got := takeString(tc.call()); got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestANSIUtilityErrors(t *testing.T) {
+	str := cString(t, styledHello)
+	for _, tc := range []struct {
+		name string
+		call func() *cChar
+	}{
+		{"Truncate", func() *cChar { return Truncate(str, -1, nil) }},
+		{"TruncateLeft", func() *cChar { return TruncateLeft(str, -1, nil) }},
+		{"Wordwrap", func() *cChar { return Wordwrap(str, -1, nil) }},
+		{"Hardwrap", func() *cChar { return Hardwrap(str, -1, 1) }},
+		{"Cut", func() *cChar { return Cut(str, 4, 2) }},
+	} {
+		expectLog(t, tc.name+" validation error", func() {
+			if got := takeString(tc.call()); got != "" {
+				t.Errorf("%s with invalid arguments = %q, want empty", tc.name, got)
+			}
+		})
+	}
+}
@@ -9,102 +9,110 @@ package main
 import "C"
 
 import (
-	"unsafe"
-
 	"github.com/charmbracelet/lipgloss"
 )
 
 // toBorder converts a lipgloss.Border to C.CBorder
 func toBorder(b lipgloss.Border) C.CBorder {
 	return C.CBorder{
-		Top:          C.CString(b.Top),
-		Bottom:       C.CString(b.Bottom),
-		Left:         C.CString(b.Left),
-		Right:        C.CString(b.Right),
-		TopLeft:      C.CString(b.TopLeft),
-		TopRight:     C.CString(b.TopRight),
-		BottomLeft:   C.CString(b.BottomLeft),
-		BottomRight:  C.CString(b.BottomRight),
-		MiddleLeft:   C.CString(b.MiddleLeft),
-		MiddleRight:  C.CString(b.MiddleRight),
-		Middle:       C.CString(b.Middle),
-		MiddleTop:    C.CString(b.MiddleTop),
-		MiddleBottom: C.CString(b.MiddleBottom),
+		Top:          Memory.CString(b.Top, "border string"),
+		Bottom:       Memory.CString(b.Bottom, "border string"),
+		Left:         Memory.CString(b.Left, "border string"),
+		Right:        Memory.CString(b.Right, "border string"),
+		TopLeft:      Memory.CString(b.TopLeft, "border string"),
+		TopRight:     Memory.CString(b.TopRight, "border string"),
+		BottomLeft:   Memory.CString(b.BottomLeft, "border string"),
+		BottomRight:  Memory.CString(b.BottomRight, "border string"),
+		MiddleLeft:   Memory.CString(b.MiddleLeft, "border string"),
+		MiddleRight:  Memory.CString(b.MiddleRight, "border string"),
+		Middle:       Memory.CString(b.Middle, "border string"),
+		MiddleTop:    Memory.CString(b.MiddleTop, "border string"),
+		MiddleBottom: Memory.CString(b.MiddleBottom, "border string"),
 	}
 }
 
 // freeBorder frees memory allocated for C.CBorder strings
 func freeBorder(b C.CBorder) {
-	C.free(unsafe.Pointer(b.Top))
-	C.free(unsafe.Pointer(b.Bottom))
-	C.free(unsafe.Pointer(b.Left))
-	C.free(unsafe.Pointer(b.Right))
-	C.free(unsafe.Pointer(b.TopLeft))
-	C.free(unsafe.Pointer(b.TopRight))
-	C.free(unsafe.Pointer(b.BottomLeft))
-	C.free(unsafe.Pointer(b.BottomRight))
-	C.free(unsafe.Pointer(b.MiddleLeft))
-	C.free(unsafe.Pointer(b.MiddleRight))
-	C.free(unsafe.Pointer(b.Middle))
-	C.free(unsafe.Pointer(b.MiddleTop))
-	C.free(unsafe.Pointer(b.MiddleBottom))
+	FreeString(b.Top)
+	FreeString(b.Bottom)
+	FreeString(b.Left)
+	FreeString(b.Right)
+	FreeString(b.TopLeft)
+	FreeString(b.TopRight)
+	FreeString(b.BottomLeft)
+	FreeString(b.BottomRight)
+	FreeString(b.MiddleLeft)
+	FreeString(b.MiddleRight)
+	FreeString(b.Middle)
+	FreeString(b.MiddleTop)
+	FreeString(b.MiddleBottom)
 }
 
 //export BlockBorder
 func BlockBorder() C.CBorder {
+	defer recoverPanic("BlockBorder")
 	Log(LogLevelDebug, "Created block border")
 	return toBorder(lipgloss.BlockBorder())
 }
 
 //export DoubleBorder
 func DoubleBorder() C.CBorder {
+	defer recoverPanic("DoubleBorder")
 	Log(LogLevelDebug, "Created double border")
 	return toBorder(lipgloss.DoubleBorder())
 }
 
 //export HiddenBorder
 func HiddenBorder() C.CBorder {
+	defer recoverPanic("HiddenBorder")
 	Log(LogLevelDebug, "Created hidden border")
 	return toBorder(lipgloss.HiddenBorder())
 }
 
 //export InnerHalfBlockBorder
 func InnerHalfBlockBorder() C.CBorder {
+	defer recoverPanic("InnerHalfBlockBorder")
 	Log(LogLevelDebug, "Created inner half block border")
 	return toBorder(lipgloss.InnerHalfBlockBorder())
 }
 
 //export NormalBorder
 func NormalBorder() C.CBorder {
+	defer recoverPanic("NormalBorder")
 	Log(LogLevelDebug, "Created normal border")
 	return toBorder(lipgloss.NormalBorder())
 }
 
 //export OuterHalfBlockBorder
 func OuterHalfBlockBorder() C.CBorder {
+	defer recoverPanic("OuterHalfBlockBorder")
 	Log(LogLevelDebug, "Created outer half block border")
 	return toBorder(lipgloss.OuterHalfBlockBorder())
 }
 
 //export RoundedBorder
 func RoundedBorder() C.CBorder {
+	defer recoverPanic("RoundedBorder")
 	Log(LogLevelDebug, "Created rounded border")
 	return toBorder(lipgloss.RoundedBorder())
 }
 
 //export ThickBorder
 func ThickBorder() C.CBorder {
+	defer recoverPanic("ThickBorder")
 	Log(LogLevelDebug, "Created thick border")
 	return toBorder(lipgloss.ThickBorder())
 }
 
 //export FreeBorder
 func FreeBorder(b C.CBorder) {
+	defer recoverPanic("FreeBorder")
 	freeBorder(b)
 }
 
 //export GetBottomSize
 func GetBottomSize(b C.CBorder) C.int {
+	defer recoverPanic("GetBottomSize")
 	border := lipgloss.Border{
 		Bottom: C.GoString(b.Bottom),
 	}
@@ -113,6 +121,7 @@ func GetBottomSize(b C.CBorder) C.int {
 
 //export GetLeftSize
 func GetLeftSize(b C.CBorder) C.int {
+	defer recoverPanic("GetLeftSize")
 	border := lipgloss.Border{
 		Left: C.GoString(b.Left),
 	}
@@ -121,6 +130,7 @@ func GetLeftSize(b C.CBorder) C.int {
 
 //export GetRightSize
 func GetRightSize(b C.CBorder) C.int {
+	defer recoverPanic("GetRightSize")
 	border := lipgloss.Border{
 		Right: C.GoString(b.Right),
 	}
@@ -129,6 +139,7 @@ func GetRightSize(b C.CBorder) C.int {
 
 //export GetTopSize
 func GetTopSize(b C.CBorder) C.int {
+	defer recoverPanic("GetTopSize")
 	border := lipgloss.Border{
 		Top: C.GoString(b.Top),
 	}
@@ -139,20 +150,21 @@ func GetTopSize(b C.CBorder) C.int {
 func CreateCustomBorder(top, bottom, left, right, topLeft, topRight,
 	bottomLeft, bottomRight, middleLeft, middleRight,
 	middle, middleTop, middleBottom *C.char) C.CBorder {
-
-	return C.CBorder{
-		Top:          C.CString(C.GoString(top)),
-		Bottom:       C.CString(C.GoString(bottom)),
-		Left:         C.CString(C.GoString(left)),
-		Right:        C.CString(C.GoString(right)),
-		TopLeft:      C.CString(C.GoString(topLeft)),
-		TopRight:     C.CString(C.GoString(topRight)),
-		BottomLeft:   C.CString(C.GoString(bottomLeft)),
-		BottomRight:  C.CString(C.GoString(bottomRight)),
-		MiddleLeft:   C.CString(C.GoString(middleLeft)),
-		MiddleRight:  C.CString(C.GoString(middleRight)),
-		Middle:       C.CString(C.GoString(middle)),
-		MiddleTop:    C.CString(C.GoString(middleTop)),
-		MiddleBottom: C.CString(C.GoString(middleBottom)),
-	}
+	defer recoverPanic("CreateCustomBorder")
+
+	return toBorder(lipgloss.Border{
+		Top:          C.GoString(top),
+		Bottom:       C.GoString(bottom),
+		Left:         C.GoString(left),
+		Right:        C.GoString(right),
+		TopLeft:      C.GoString(topLeft),
+		TopRight:     C.GoString(topRight),
+		BottomLeft:   C.GoString(bottomLeft),
+		BottomRight:  C.GoString(bottomRight),
+		MiddleLeft:   C.GoString(middleLeft),
+		MiddleRight:  C.GoString(middleRight),
+		Middle:       C.GoString(middle),
+		MiddleTop:    C.GoString(middleTop),
+		MiddleBottom: C.GoString(middleBottom),
+	})
 }
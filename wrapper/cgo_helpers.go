@@ -0,0 +1,267 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+// Helpers for the Go tests, which cannot use cgo themselves: aliases for
+// the C types of export parameters, conversions, C callbacks and drivers
+// that call the exports from C. They are only built with the
+// lipgloss_testhelpers tag, so they stay out of the library.
+
+/*
+#include <stdio.h>
+#include <stdlib.h>
+#include <stdint.h>
+#include <string.h>
+#include <ctype.h>
+#include "lipgloss_types.h"
+
+// Prototypes of exports, so the helpers below call them the way a C
+// program does, crossing the cgo boundary on every call
+extern char* StyleRender(uint64_t id, char* str);
+extern size_t StyleRenderBatch(CHandleIn* styles, CStringIn* texts, size_t n, char** out);
+extern void FreeString(char* str);
+
+static inline void renderEach(uint64_t* styles, char** texts, size_t n) {
+	for (size_t i = 0; i < n; i++) {
+		FreeString(StyleRender(styles[i], texts[i]));
+	}
+}
+
+static inline size_t renderBatch(uint64_t* styles, char** texts, size_t n, char** out) {
+	size_t rendered = StyleRenderBatch((CHandleIn*)styles, (CStringIn*)texts, n, out);
+	for (size_t i = 0; i < n; i++) {
+		FreeString(out[i]);
+	}
+	return rendered;
+}
+
+// Callbacks used by the tests. userdata, where used, points to a uint64_t
+// style handle.
+static inline uint64_t evenItemStyle(int count, int index, void* userdata) {
+	return index % 2 == 0 ? *(uint64_t*)userdata : 0;
+}
+
+static inline const char* arrowEnumerator(int count, int index, void* userdata) {
+	return index == count - 1 ? "=>" : "->";
+}
+
+static inline const char* barIndenter(int count, int index, void* userdata) {
+	return "|  ";
+}
+
+static inline char* upperTransform(const char* content, void* userdata) {
+	char* out = strdup(content);
+	for (char* p = out; *p; p++) {
+		*p = toupper((unsigned char)*p);
+	}
+	return out;
+}
+
+// maskTransform rewrites the content in place and hands it back
+static inline char* maskTransform(const char* content, void* userdata) {
+	char* out = (char*)content;
+	for (char* p = out; *p; p++) {
+		*p = '*';
+	}
+	return out;
+}
+
+// Go cannot take the address of a static C function, so hand out pointers
+static inline CStyleFunc evenItemStyleFunc(void) { return evenItemStyle; }
+static inline CListEnumeratorFunc arrowListEnumerator(void) { return arrowEnumerator; }
+static inline CTreeEnumeratorFunc arrowTreeEnumerator(void) { return arrowEnumerator; }
+static inline CTreeIndenterFunc barTreeIndenter(void) { return barIndenter; }
+static inline CTransformFunc upperTransformFunc(void) { return upperTransform; }
+static inline CTransformFunc maskTransformFunc(void) { return maskTransform; }
+*/
+import "C"
+import "unsafe"
+
+type (
+	cChar   = C.char
+	cInt    = C.int
+	cDouble = C.double
+	cFloat  = C.float
+	cSize   = C.size_t
+	cHandle = C.uint64_t
+	cUint32 = C.uint32_t
+	cBorder = C.CBorder
+)
+
+// Statuses of the *_Into render functions
+const (
+	renderOK             = C.RENDER_OK
+	renderBufferTooSmall = C.RENDER_BUFFER_TOO_SMALL
+	renderError          = C.RENDER_ERROR
+)
+
+// Enumerator and indenter types of trees and lists
+const (
+	treeEnumRounded  = C.TREE_ENUM_ROUNDED
+	treeEnumASCII    = C.TREE_ENUM_ASCII
+	treeIndentSpaces = C.TREE_INDENT_SPACES
+	treeIndentASCII  = C.TREE_INDENT_ASCII
+	treeIndentBlank  = C.TREE_INDENT_BLANK
+	listEnumDash     = C.LIST_ENUM_DASH
+	listEnumAlphabet = C.LIST_ENUM_ALPHABET
+	listEnumArabic   = C.LIST_ENUM_ARABIC
+	listEnumRoman    = C.LIST_ENUM_ROMAN
+	listEnumAsterisk = C.LIST_ENUM_ASTERISK
+	listEnumCheckbox = C.LIST_ENUM_CHECKBOX
+)
+
+// cleaner is the part of testing.TB the helpers need
+type cleaner interface {
+	Cleanup(func())
+}
+
+// cString returns a C copy of s that is freed when the test ends
+func cString(tb cleaner, s string) *C.char {
+	cs := C.CString(s)
+	tb.Cleanup(func() { C.free(unsafe.Pointer(cs)) })
+	return cs
+}
+
+// cStrings returns a C array of C copies of ss, freed when the test ends
+func cStrings(tb cleaner, ss []string) **C.char {
+	arr := cStringArray(tb, len(ss))
+	elems := unsafe.Slice(arr, len(ss))
+	for i, s := range ss {
+		elems[i] = cString(tb, s)
+	}
+	return arr
+}
+
+// cStringArray returns a zeroed C array of n strings, freed when the test
+// ends. The strings stored in it are not.
+func cStringArray(tb cleaner, n int) **C.char {
+	arr := (**C.char)(C.calloc(C.size_t(max(n, 1)), C.size_t(unsafe.Sizeof((*C.char)(nil)))))
+	tb.Cleanup(func() { C.free(unsafe.Pointer(arr)) })
+	return arr
+}
+
+// cInts returns a C copy of ints, freed when the test ends
+func cInts(tb cleaner, ints []int) *C.int {
+	arr := (*C.int)(C.calloc(C.size_t(max(len(ints), 1)), C.size_t(unsafe.Sizeof(C.int(0)))))
+	elems := unsafe.Slice(arr, len(ints))
+	for i, v := range ints {
+		elems[i] = C.int(v)
+	}
+	tb.Cleanup(func() { C.free(unsafe.Pointer(arr)) })
+	return arr
+}
+
+// cBuffer returns a zeroed C buffer of n bytes, freed when the test ends
+func cBuffer(tb cleaner, n int) *C.char {
+	buf := (*C.char)(C.calloc(C.size_t(max(n, 1)), 1))
+	tb.Cleanup(func() { C.free(unsafe.Pointer(buf)) })
+	return buf
+}
+
+// cHandlePtr returns a C copy of a handle, for callbacks taking it as
+// userdata
+func cHandlePtr(tb cleaner, id C.uint64_t) unsafe.Pointer {
+	p := C.malloc(C.size_t(unsafe.Sizeof(id)))
+	*(*C.uint64_t)(p) = id
+	tb.Cleanup(func() { C.free(p) })
+	return p
+}
+
+// peekString copies a C string the library still owns
+func peekString(cs *C.char) string {
+	return String.GoString(cs)
+}
+
+// takeString copies a string returned by an export and frees it
+func takeString(cs *C.char) string {
+	defer FreeString(cs)
+	return String.GoString(cs)
+}
+
+// takeStrings copies and frees the n strings an export stored in arr
+func takeStrings(arr **C.char, n int) []string {
+	out := make([]string, n)
+	for i, cs := range unsafe.Slice(arr, n) {
+		out[i] = takeString(cs)
+	}
+	return out
+}
+
+// devNull opens /dev/null as a C stream. It is never closed: the renderer
+// wraps its descriptor in an os.File that closes it once collected.
+func devNull() *C.FILE {
+	mode := C.CString("w")
+	defer C.free(unsafe.Pointer(mode))
+	path := C.CString("/dev/null")
+	defer C.free(unsafe.Pointer(path))
+	return C.fopen(path, mode)
+}
+
+// cSVGOptions returns zeroed RenderToSVG options with a title and window
+// chrome, freed when the test ends
+func cSVGOptions(tb cleaner, title string) *C.CSVGOptions {
+	opts := (*C.CSVGOptions)(C.calloc(1, C.size_t(unsafe.Sizeof(C.CSVGOptions{}))))
+	opts.window_chrome = 1
+	opts.title = cString(tb, title)
+	tb.Cleanup(func() { C.free(unsafe.Pointer(opts)) })
+	return opts
+}
+
+func evenItemStyleFunc() C.CStyleFunc            { return C.evenItemStyleFunc() }
+func arrowListEnumerator() C.CListEnumeratorFunc { return C.arrowListEnumerator() }
+func arrowTreeEnumerator() C.CTreeEnumeratorFunc { return C.arrowTreeEnumerator() }
+func barTreeIndenter() C.CTreeIndenterFunc       { return C.barTreeIndenter() }
+func upperTransformFunc() C.CTransformFunc       { return C.upperTransformFunc() }
+func maskTransformFunc() C.CTransformFunc        { return C.maskTransformFunc() }
+
+// cRenderInputs holds C copies of (style, text) pairs so tests and
+// benchmarks can drive the render exports from C.
+type cRenderInputs struct {
+	n      int
+	styles *C.uint64_t
+	texts  **C.char
+	out    **C.char
+}
+
+func newCRenderInputs(styles []uint64, texts []string) *cRenderInputs {
+	n := len(styles)
+	in := &cRenderInputs{
+		n:      n,
+		styles: (*C.uint64_t)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof(C.uint64_t(0))))),
+		texts:  (**C.char)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil))))),
+		out:    (**C.char)(C.malloc(C.size_t(n) * C.size_t(unsafe.Sizeof((*C.char)(nil))))),
+	}
+	cStyles := unsafe.Slice(in.styles, n)
+	cTexts := unsafe.Slice(in.texts, n)
+	for i := range styles {
+		cStyles[i] = C.uint64_t(styles[i])
+		cTexts[i] = C.CString(texts[i])
+	}
+	return in
+}
+
+// renderEach calls StyleRender once per pair
+func (in *cRenderInputs) renderEach() {
+	C.renderEach(in.styles, in.texts, C.size_t(in.n))
+}
+
+// renderBatch renders every pair with one StyleRenderBatch call
+func (in *cRenderInputs) renderBatch() int {
+	return int(C.renderBatch(in.styles, in.texts, C.size_t(in.n), in.out))
+}
+
+// renderBatchStrings calls StyleRenderBatch and returns the number of
+// pairs rendered along with the strings it stored, which it frees
+func (in *cRenderInputs) renderBatchStrings() (int, []string) {
+	n := StyleRenderBatch((*C.CHandleIn)(unsafe.Pointer(in.styles)), (*C.CStringIn)(unsafe.Pointer(in.texts)), C.size_t(in.n), in.out)
+	return int(n), takeStrings(in.out, in.n)
+}
+
+func (in *cRenderInputs) free() {
+	for _, cs := range unsafe.Slice(in.texts, in.n) {
+		C.free(unsafe.Pointer(cs))
+	}
+	C.free(unsafe.Pointer(in.styles))
+	C.free(unsafe.Pointer(in.texts))
+	C.free(unsafe.Pointer(in.out))
+}
@@ -0,0 +1,161 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"math"
+	"unsafe"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+)
+
+// colorResult copies a color into a tracked #rrggbb C string
+func colorResult(c colorful.Color, op string) *C.char {
+	cs, err := String.CString(c.Clamped().Hex())
+	if err != nil {
+		Log(LogLevelError, "%s memory allocation error: %v", op, err)
+		return Memory.CString("", op+" result")
+	}
+	Memory.Track(unsafe.Pointer(cs), op+" result")
+	return cs
+}
+
+// adjustHSL shifts a color's HSL saturation and lightness by the given
+// amounts, clamping both to [0, 1]
+func adjustHSL(color *C.char, dSat, dLight float64, op string) *C.char {
+	if math.IsNaN(dSat) || math.IsNaN(dLight) {
+		Log(LogLevelError, "%s error: amount is NaN", op)
+		return Memory.CString("", op+" result")
+	}
+	c, err := parseRGBColor(String.GoString(color))
+	if err != nil {
+		Log(LogLevelError, "%s error: %v", op, err)
+		return Memory.CString("", op+" result")
+	}
+	h, s, l := c.Hsl()
+	s = min(max(s+dSat, 0), 1)
+	l = min(max(l+dLight, 0), 1)
+	return colorResult(colorful.Hsl(h, s, l), op)
+}
+
+// relativeLuminance implements the WCAG 2 definition
+func relativeLuminance(c colorful.Color) float64 {
+	r, g, b := c.Clamped().LinearRgb()
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+// contrastRatio returns the WCAG contrast ratio of two colors, from 1 to 21
+func contrastRatio(a, b colorful.Color) float64 {
+	la, lb := relativeLuminance(a), relativeLuminance(b)
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// ColorLighten raises a color's HSL lightness by amount (0-1).
+//
+//export ColorLighten
+func ColorLighten(color *C.char, amount C.double) (ret *C.char) {
+	defer recoverString("ColorLighten", &ret, "")
+	return adjustHSL(color, 0, float64(amount), "ColorLighten")
+}
+
+// ColorDarken lowers a color's HSL lightness by amount (0-1).
+//
+//export ColorDarken
+func ColorDarken(color *C.char, amount C.double) (ret *C.char) {
+	defer recoverString("ColorDarken", &ret, "")
+	return adjustHSL(color, 0, -float64(amount), "ColorDarken")
+}
+
+// ColorSaturate raises a color's HSL saturation by amount (0-1). Negative
+// amounts desaturate.
+//
+//export ColorSaturate
+func ColorSaturate(color *C.char, amount C.double) (ret *C.char) {
+	defer recoverString("ColorSaturate", &ret, "")
+	return adjustHSL(color, float64(amount), 0, "ColorSaturate")
+}
+
+// ColorContrastRatio returns the WCAG contrast ratio between two colors,
+// from 1 (identical) to 21 (black on white), or 0 on error. WCAG AA asks
+// for at least 4.5 for body text and 3 for large text.
+//
+//export ColorContrastRatio
+func ColorContrastRatio(foreground, background *C.char) C.double {
+	defer recoverPanic("ColorContrastRatio")
+	fg, err := parseRGBColor(String.GoString(foreground))
+	if err != nil {
+		Log(LogLevelError, "ColorContrastRatio foreground error: %v", err)
+		return 0
+	}
+	bg, err := parseRGBColor(String.GoString(background))
+	if err != nil {
+		Log(LogLevelError, "ColorContrastRatio background error: %v", err)
+		return 0
+	}
+	return C.double(contrastRatio(fg, bg))
+}
+
+// ColorReadableText picks the candidate with the highest contrast against
+// background. With no candidates it chooses between black and white.
+//
+//export ColorReadableText
+func ColorReadableText(background *C.char, candidates **C.char, count C.int) (ret *C.char) {
+	defer recoverString("ColorReadableText", &ret, "")
+	bg, err := parseRGBColor(String.GoString(background))
+	if err != nil {
+		Log(LogLevelError, "ColorReadableText background error: %v", err)
+		return Memory.CString("", "ColorReadableText result")
+	}
+
+	options := []colorful.Color{{R: 0, G: 0, B: 0}, {R: 1, G: 1, B: 1}}
+	if count > 0 {
+		if options, err = parseRGBColors(candidates, count); err != nil {
+			Log(LogLevelError, "ColorReadableText candidate error: %v", err)
+			return Memory.CString("", "ColorReadableText result")
+		}
+	}
+
+	best := options[0]
+	for _, c := range options[1:] {
+		if contrastRatio(c, bg) > contrastRatio(best, bg) {
+			best = c
+		}
+	}
+	return colorResult(best, "ColorReadableText")
+}
+
+// ColorToANSI256 returns the index of the xterm 256-color palette entry
+// closest to color, or -1 on error.
+//
+//export ColorToANSI256
+func ColorToANSI256(color *C.char) (ret C.int) {
+	defer recoverValue("ColorToANSI256", &ret, -1)
+	c, err := parseRGBColor(String.GoString(color))
+	if err != nil {
+		Log(LogLevelError, "ColorToANSI256 error: %v", err)
+		return -1
+	}
+	return C.int(termenv.ANSI256.Convert(termenv.RGBColor(c.Hex())).(termenv.ANSI256Color))
+}
+
+// ColorToANSI16 returns the index (0-15) of the basic ANSI color closest to
+// color, or -1 on error.
+//
+//export ColorToANSI16
+func ColorToANSI16(color *C.char) (ret C.int) {
+	defer recoverValue("ColorToANSI16", &ret, -1)
+	c, err := parseRGBColor(String.GoString(color))
+	if err != nil {
+		Log(LogLevelError, "ColorToANSI16 error: %v", err)
+		return -1
+	}
+	return C.int(termenv.ANSI.Convert(termenv.RGBColor(c.Hex())).(termenv.ANSIColor))
+}
@@ -0,0 +1,102 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestColorAdjust(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		call func() *cChar
+		want string
+	}{
+		{"ColorLighten", func() *cChar { return ColorLighten(cString(t, "#800000"), 0.25) }, "#ff0000"},
+		{"ColorDarken", func() *cChar { return ColorDarken(cString(t, "#ff0000"), 0.25) }, "#800000"},
+		{"ColorDarken past black", func() *cChar { return ColorDarken(cString(t, "#ff0000"), 2) }, "#000000"},
+		{"ColorSaturate", func() *cChar { return ColorSaturate(cString(t, "#808080"), 1) }, "#ff0101"},
+		{"ColorLighten ANSI", func() *cChar { return ColorLighten(cString(t, "0"), 1) }, "#ffffff"},
+	} {
+		if got := takeString(tc.call()); got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	for name, fn := range map[string]func(*cChar, cDouble) *cChar{
+		"ColorLighten":  ColorLighten,
+		"ColorDarken":   ColorDarken,
+		"ColorSaturate": ColorSaturate,
+	} {
+		expectLog(t, name+" error", func() {
+			if got := takeString(fn(cString(t, "red"), 0.1)); got != "" {
+				t.Errorf("%s of an invalid color = %q, want empty", name, got)
+			}
+		})
+		expectLog(t, name+" error: amount is NaN", func() {
+			if got := takeString(fn(cString(t, "#808080"), cDouble(math.NaN()))); got != "" {
+				t.Errorf("%s by NaN = %q, want empty", name, got)
+			}
+		})
+	}
+}
+
+func TestColorContrast(t *testing.T) {
+	if got := ColorContrastRatio(cString(t, "#000"), cString(t, "#fff")); math.Abs(float64(got)-21) > 1e-9 {
+		t.Errorf("ColorContrastRatio(black, white) = %v, want 21", got)
+	}
+	if got := ColorContrastRatio(cString(t, "#fff"), cString(t, "#fff")); got != 1 {
+		t.Errorf("ColorContrastRatio(white, white) = %v, want 1", got)
+	}
+	expectLog(t, "foreground error", func() {
+		if got := ColorContrastRatio(cString(t, "#ggg"), cString(t, "#fff")); got != 0 {
+			t.Errorf("ColorContrastRatio with an invalid color = %v", got)
+		}
+	})
+	expectLog(t, "background error", func() {
+		if got := ColorContrastRatio(cString(t, "#fff"), nil); got != 0 {
+			t.Errorf("ColorContrastRatio with no background = %v", got)
+		}
+	})
+
+	candidates := cStrings(t, []string{"#777777", "#111111", "#eeeeee"})
+	if got := takeString(ColorReadableText(cString(t, "#202020"), candidates, 3)); got != "#eeeeee" {
+		t.Errorf("ColorReadableText on dark gray = %q, want #eeeeee", got)
+	}
+	if got := takeString(ColorReadableText(cString(t, "#f0f0f0"), candidates, 3)); got != "#111111" {
+		t.Errorf("ColorReadableText on light gray = %q, want #111111", got)
+	}
+	if got := takeString(ColorReadableText(cString(t, "#f0f0f0"), nil, 0)); got != "#000000" {
+		t.Errorf("ColorReadableText without candidates = %q, want black", got)
+	}
+	expectLog(t, "candidate error", func() {
+		if got := takeString(ColorReadableText(cString(t, "#fff"), cStrings(t, []string{"#fff", "#0000"}), 2)); got != "" {
+			t.Errorf("ColorReadableText with an invalid candidate = %q", got)
+		}
+	})
+	expectLog(t, "background error", func() {
+		if got := takeString(ColorReadableText(cString(t, "256"), candidates, 3)); got != "" {
+			t.Errorf("ColorReadableText with an invalid background = %q", got)
+		}
+	})
+}
+
+func TestColorToANSI(t *testing.T) {
+	if got := ColorToANSI256(cString(t, "#ff0000")); got != 196 {
+		t.Errorf("ColorToANSI256(#ff0000) = %d, want 196", got)
+	}
+	if got := ColorToANSI16(cString(t, "#ff0000")); got != 9 {
+		t.Errorf("ColorToANSI16(#ff0000) = %d, want 9", got)
+	}
+	expectLog(t, "ColorToANSI256 error", func() {
+		if got := ColorToANSI256(cString(t, "#12345")); got != -1 {
+			t.Errorf("ColorToANSI256 of an invalid color = %d, want -1", got)
+		}
+	})
+	expectLog(t, "ColorToANSI16 error", func() {
+		if got := ColorToANSI16(nil); got != -1 {
+			t.Errorf("ColorToANSI16(NULL) = %d, want -1", got)
+		}
+	})
+}
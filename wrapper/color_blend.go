@@ -0,0 +1,212 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/muesli/termenv"
+	"github.com/rivo/uniseg"
+)
+
+// parseRGBColor reads a hex color (#rgb or #rrggbb) or an ANSI color
+// index (0-255) as an RGB color
+func parseRGBColor(s string) (colorful.Color, error) {
+	if strings.HasPrefix(s, "#") {
+		if len(s) == 4 {
+			s = string([]byte{'#', s[1], s[1], s[2], s[2], s[3], s[3]})
+		}
+		c, err := colorful.Hex(s)
+		if err != nil || len(s) != 7 {
+			return colorful.Color{}, fmt.Errorf("invalid hex color %q", s)
+		}
+		return c, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 || n > 255 {
+		return colorful.Color{}, fmt.Errorf("invalid color %q, expected #rgb, #rrggbb or an ANSI index 0-255", s)
+	}
+	return termenv.ConvertToRGB(termenv.ANSI256Color(n)), nil
+}
+
+// parseRGBColors reads count colors from a C string array
+func parseRGBColors(colors **C.char, count C.int) ([]colorful.Color, error) {
+	if count < 1 || colors == nil {
+		return nil, fmt.Errorf("at least one color is required")
+	}
+	stops := make([]colorful.Color, int(count))
+	for i, cs := range unsafe.Slice(colors, int(count)) {
+		c, err := parseRGBColor(String.GoString(cs))
+		if err != nil {
+			return nil, fmt.Errorf("color %d: %w", i, err)
+		}
+		stops[i] = c
+	}
+	return stops, nil
+}
+
+// gradient spreads n colors evenly over the stops, blending neighbouring
+// stops in CIE L*a*b* so the steps look uniform
+func gradient(stops []colorful.Color, n int) []colorful.Color {
+	colors := make([]colorful.Color, n)
+	for i := range colors {
+		if len(stops) == 1 || n == 1 {
+			colors[i] = stops[0]
+			continue
+		}
+		pos := float64(i) / float64(n-1) * float64(len(stops)-1)
+		seg := int(pos)
+		if seg >= len(stops)-1 {
+			seg = len(stops) - 2
+		}
+		colors[i] = stops[seg].BlendLab(stops[seg+1], pos-float64(seg)).Clamped()
+	}
+	return colors
+}
+
+// activeColorProfile returns the profile of the renderer styles are drawn
+// with
+func activeColorProfile() termenv.Profile {
+	if renderer := GetRenderer(); renderer != nil {
+		return renderer.ColorProfile()
+	}
+	return lipgloss.ColorProfile()
+}
+
+// renderGradient colors text column by column. Every line uses the same
+// gradient, stretched over the widest line, so multi-line text such as
+// banners gets vertical bands of color.
+func renderGradient(text string, stops []colorful.Color, profile termenv.Profile) string {
+	lines := strings.Split(text, "\n")
+	width := 0
+	for _, line := range lines {
+		width = max(width, ansi.StringWidth(line))
+	}
+	if width == 0 {
+		return text
+	}
+	colors := gradient(stops, width)
+
+	for i, line := range lines {
+		var b strings.Builder
+		var run strings.Builder
+		var runColor termenv.Color
+		flush := func() {
+			if run.Len() > 0 {
+				b.WriteString(profile.String(run.String()).Foreground(runColor).String())
+				run.Reset()
+			}
+		}
+
+		col := 0
+		state := -1
+		rest := line
+		for len(rest) > 0 {
+			var cluster string
+			var w int
+			cluster, rest, w, state = uniseg.FirstGraphemeClusterInString(rest, state)
+			color := profile.Color(colors[min(col, width-1)].Hex())
+			if color != runColor {
+				flush()
+				runColor = color
+			}
+			run.WriteString(cluster)
+			col += w
+		}
+		flush()
+		lines[i] = b.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BlendColor mixes two colors, returning a at t=0 and b at t=1.
+//
+//export BlendColor
+func BlendColor(a, b *C.char, t C.double) (ret *C.char) {
+	defer recoverString("BlendColor", &ret, "")
+	if math.IsNaN(float64(t)) {
+		Log(LogLevelError, "BlendColor error: t is NaN")
+		return Memory.CString("", "BlendColor result")
+	}
+	from, err := parseRGBColor(String.GoString(a))
+	if err != nil {
+		Log(LogLevelError, "BlendColor error: %v", err)
+		return Memory.CString("", "BlendColor result")
+	}
+	to, err := parseRGBColor(String.GoString(b))
+	if err != nil {
+		Log(LogLevelError, "BlendColor error: %v", err)
+		return Memory.CString("", "BlendColor result")
+	}
+
+	return colorResult(from.BlendLab(to, min(max(float64(t), 0), 1)), "BlendColor")
+}
+
+// BlendColors writes n colors running evenly through the count color stops
+// into out, which must have room for n strings. Each string must be freed
+// with FreeString. Returns the number of colors written, 0 on error.
+//
+//export BlendColors
+func BlendColors(colors **C.char, count C.int, n C.int, out **C.char) C.int {
+	defer recoverPanic("BlendColors")
+	if n < 1 || out == nil {
+		Log(LogLevelError, "BlendColors requires n >= 1 and an output array (n=%d)", int(n))
+		return 0
+	}
+	stops, err := parseRGBColors(colors, count)
+	if err != nil {
+		Log(LogLevelError, "BlendColors error: %v", err)
+		return 0
+	}
+
+	results := unsafe.Slice(out, int(n))
+	for i, c := range gradient(stops, int(n)) {
+		cs, err := String.CString(c.Hex())
+		if err != nil {
+			Log(LogLevelError, "BlendColors memory allocation error: %v", err)
+			for _, prev := range results[:i] {
+				FreeString(prev)
+			}
+			return 0
+		}
+		Memory.Track(unsafe.Pointer(cs), "BlendColors result")
+		results[i] = cs
+	}
+	return n
+}
+
+// RenderGradient gives every cell of text a foreground color taken from a
+// gradient through the n color stops, downsampled to the active color
+// profile. text should be plain; style it further by passing the result
+// through StyleRender.
+//
+//export RenderGradient
+func RenderGradient(text *C.char, colors **C.char, n C.int) (ret *C.char) {
+	defer recoverString("RenderGradient", &ret, "")
+	stops, err := parseRGBColors(colors, n)
+	if err != nil {
+		Log(LogLevelError, "RenderGradient error: %v", err)
+		return Memory.CString("", "RenderGradient result")
+	}
+
+	result := renderGradient(String.GoString(text), stops, activeColorProfile())
+	cs, err := String.CString(result)
+	if err != nil {
+		Log(LogLevelError, "RenderGradient memory allocation error: %v", err)
+		return Memory.CString("", "RenderGradient result")
+	}
+	Memory.Track(unsafe.Pointer(cs), "RenderGradient result")
+	return cs
+}
@@ -0,0 +1,72 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBlendColor(t *testing.T) {
+	black, white := cString(t, "#000000"), cString(t, "#ffffff")
+	if got := takeString(BlendColor(black, white, 0)); got != "#000000" {
+		t.Errorf("BlendColor at 0 = %q", got)
+	}
+	if got := takeString(BlendColor(black, white, 2)); got != "#ffffff" {
+		t.Errorf("BlendColor past 1 = %q, want the end color", got)
+	}
+	if got := takeString(BlendColor(black, white, 0.5)); got != "#777777" {
+		t.Errorf("BlendColor at 0.5 = %q, want the Lab midpoint #777777", got)
+	}
+	expectLog(t, "BlendColor error: t is NaN", func() {
+		if got := takeString(BlendColor(black, white, cDouble(math.NaN()))); got != "" {
+			t.Errorf("BlendColor at NaN = %q", got)
+		}
+	})
+	for _, invalid := range []string{"white", "#ff00000", "#ff00"} {
+		expectLog(t, "BlendColor error", func() {
+			if got := takeString(BlendColor(black, cString(t, invalid), 0.5)); got != "" {
+				t.Errorf("BlendColor with the invalid color %q = %q", invalid, got)
+			}
+		})
+	}
+}
+
+func TestBlendColors(t *testing.T) {
+	stops := cStrings(t, []string{"#ff0000", "#0000ff"})
+	out := cStringArray(t, 3)
+	if got := BlendColors(stops, 2, 3, out); got != 3 {
+		t.Fatalf("BlendColors returned %d, want 3", got)
+	}
+	colors := takeStrings(out, 3)
+	if colors[0] != "#ff0000" || colors[2] != "#0000ff" {
+		t.Errorf("BlendColors = %v, want it to start and end on the stops", colors)
+	}
+
+	expectLog(t, "requires n >= 1", func() {
+		if got := BlendColors(stops, 2, 0, out); got != 0 {
+			t.Errorf("BlendColors with n=0 = %d", got)
+		}
+	})
+	expectLog(t, "requires n >= 1", func() {
+		if got := BlendColors(stops, 2, 3, nil); got != 0 {
+			t.Errorf("BlendColors without an output array = %d", got)
+		}
+	})
+	expectLog(t, "BlendColors error", func() {
+		if got := BlendColors(cStrings(t, []string{"#ff0000", "nope"}), 2, 3, out); got != 0 {
+			t.Errorf("BlendColors with an invalid stop = %d", got)
+		}
+	})
+}
+
+func TestRenderGradient(t *testing.T) {
+	stops := cStrings(t, []string{"#ff5f87", "#5fafff", "#87d787"})
+	golden(t, "gradient", takeString(RenderGradient(cString(t, "Gradients!\nTwo lines"), stops, 3)))
+
+	expectLog(t, "RenderGradient error", func() {
+		if got := takeString(RenderGradient(cString(t, "x"), stops, 0)); got != "" {
+			t.Errorf("RenderGradient without stops = %q", got)
+		}
+	})
+}
@@ -16,15 +16,16 @@ import (
 )
 
 //export MapTerminalColor
-func MapTerminalColor(tcHandle unsafe.Pointer) *C.char {
+func MapTerminalColor(tcHandle unsafe.Pointer) (ret *C.char) {
+	defer recoverString("MapTerminalColor", &ret, "")
 	if tcHandle == nil {
-		return C.CString("")
+		return Memory.CString("", "MapTerminalColor result")
 	}
 
 	tc := *(*lipgloss.TerminalColor)(tcHandle)
 	renderer := GetRenderer()
 	if renderer == nil {
-		return C.CString("")
+		return Memory.CString("", "MapTerminalColor result")
 	}
 
 	var colorStr string
@@ -65,11 +66,12 @@ func MapTerminalColor(tcHandle unsafe.Pointer) *C.char {
 		colorStr = ""
 	}
 
-	return C.CString(colorStr)
+	return Memory.CString(colorStr, "MapTerminalColor result")
 }
 
 //export GetTerminalColorRGBA
 func GetTerminalColorRGBA(tcHandle unsafe.Pointer) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("GetTerminalColorRGBA", &r, &g, &b, &a)
 	if tcHandle == nil {
 		return 0, 0, 0, 0xFFFF
 	}
@@ -91,6 +93,7 @@ func (c Color) color(r *lipgloss.Renderer) termenv.Color {
 
 //export ColorRGBA
 func ColorRGBA(c *C.char) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("ColorRGBA", &r, &g, &b, &a)
 	if c == nil {
 		return 0, 0, 0, 0xFFFF
 	}
@@ -117,6 +120,7 @@ func (ac ANSIColor) color(r *lipgloss.Renderer) termenv.Color {
 
 //export ANSIColorRGBA
 func ANSIColorRGBA(value C.uint) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("ANSIColorRGBA", &r, &g, &b, &a)
 	ac := ANSIColor(value)
 	rVal, gVal, bVal, aVal := ac.RGBA()
 	return C.uint32_t(rVal), C.uint32_t(gVal), C.uint32_t(bVal), C.uint32_t(aVal)
@@ -144,6 +148,7 @@ func (ac AdaptiveColor) color(r *lipgloss.Renderer) termenv.Color {
 
 //export AdaptiveColorRGBA
 func AdaptiveColorRGBA(light, dark *C.char) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("AdaptiveColorRGBA", &r, &g, &b, &a)
 	if light == nil || dark == nil {
 		return 0, 0, 0, 0xFFFF
 	}
@@ -187,6 +192,7 @@ func (cc CompleteColor) color(r *lipgloss.Renderer) termenv.Color {
 
 //export CompleteColorRGBA
 func CompleteColorRGBA(trueColor, ansi256, ansi *C.char) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("CompleteColorRGBA", &r, &g, &b, &a)
 	if trueColor == nil || ansi256 == nil || ansi == nil {
 		return 0, 0, 0, 0xFFFF
 	}
@@ -226,6 +232,7 @@ func (cac CompleteAdaptiveColor) color(r *lipgloss.Renderer) termenv.Color {
 
 //export CompleteAdaptiveColorRGBA
 func CompleteAdaptiveColorRGBA(lightTrue, lightANSI256, lightANSI, darkTrue, darkANSI256, darkANSI *C.char) (r, g, b, a C.uint32_t) {
+	defer recoverRGBA("CompleteAdaptiveColorRGBA", &r, &g, &b, &a)
 	if lightTrue == nil || lightANSI256 == nil || lightANSI == nil ||
 		darkTrue == nil || darkANSI256 == nil || darkANSI == nil {
 		return 0, 0, 0, 0xFFFF
@@ -0,0 +1,81 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// withDefaultRenderer makes the lipgloss default renderer, pinned to
+// truecolor on a dark background by TestMain, the active renderer
+func withDefaultRenderer(t *testing.T) {
+	DefaultRenderer()
+	t.Cleanup(func() { setRenderer(nil, nil) })
+}
+
+type rgba struct{ r, g, b, a uint32 }
+
+func rgbaOf(r, g, b, a cUint32) rgba {
+	return rgba{uint32(r), uint32(g), uint32(b), uint32(a)}
+}
+
+func TestColorRGBA(t *testing.T) {
+	red := rgba{0xffff, 0, 0, 0xffff}
+	blue := rgba{0, 0, 0xffff, 0xffff}
+	none := rgba{0, 0, 0, 0xffff}
+
+	// Without a renderer every color is opaque black
+	if got := rgbaOf(ColorRGBA(cString(t, "#ff0000"))); got != none {
+		t.Errorf("ColorRGBA without a renderer = %v, want %v", got, none)
+	}
+
+	withDefaultRenderer(t)
+	for _, tc := range []struct {
+		name string
+		got  rgba
+		want rgba
+	}{
+		{"ColorRGBA", rgbaOf(ColorRGBA(cString(t, "#ff0000"))), red},
+		{"ColorRGBA(NULL)", rgbaOf(ColorRGBA(nil)), none},
+		{"ANSIColorRGBA", rgbaOf(ANSIColorRGBA(12)), blue},
+		{"AdaptiveColorRGBA", rgbaOf(AdaptiveColorRGBA(cString(t, "#ff0000"), cString(t, "#0000ff"))), blue},
+		{"AdaptiveColorRGBA(NULL)", rgbaOf(AdaptiveColorRGBA(nil, cString(t, "#0000ff"))), none},
+		{"CompleteColorRGBA", rgbaOf(CompleteColorRGBA(cString(t, "#ff0000"), cString(t, "21"), cString(t, "4"))), red},
+		{"CompleteColorRGBA(NULL)", rgbaOf(CompleteColorRGBA(cString(t, "#ff0000"), nil, cString(t, "4"))), none},
+		{"CompleteAdaptiveColorRGBA", rgbaOf(CompleteAdaptiveColorRGBA(
+			cString(t, "#ff0000"), cString(t, "196"), cString(t, "9"),
+			cString(t, "#0000ff"), cString(t, "21"), cString(t, "12"))), blue},
+		{"CompleteAdaptiveColorRGBA(NULL)", rgbaOf(CompleteAdaptiveColorRGBA(
+			cString(t, "#ff0000"), cString(t, "196"), cString(t, "9"), nil, nil, nil)), none},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
+
+func TestTerminalColor(t *testing.T) {
+	var tc lipgloss.TerminalColor = lipgloss.AdaptiveColor{Light: "#111111", Dark: "#eeeeee"}
+	handle := unsafe.Pointer(&tc)
+
+	if got := takeString(MapTerminalColor(handle)); got != "" {
+		t.Errorf("MapTerminalColor without a renderer = %q, want empty", got)
+	}
+	withDefaultRenderer(t)
+	if got := takeString(MapTerminalColor(handle)); got != "#eeeeee" {
+		t.Errorf("MapTerminalColor on a dark background = %q, want #eeeeee", got)
+	}
+	if got := takeString(MapTerminalColor(nil)); got != "" {
+		t.Errorf("MapTerminalColor(NULL) = %q, want empty", got)
+	}
+
+	if got, want := rgbaOf(GetTerminalColorRGBA(handle)), (rgba{0xeeee, 0xeeee, 0xeeee, 0xffff}); got != want {
+		t.Errorf("GetTerminalColorRGBA = %v, want %v", got, want)
+	}
+	if got, want := rgbaOf(GetTerminalColorRGBA(nil)), (rgba{0, 0, 0, 0xffff}); got != want {
+		t.Errorf("GetTerminalColorRGBA(NULL) = %v, want %v", got, want)
+	}
+}
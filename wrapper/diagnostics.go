@@ -0,0 +1,217 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// registryStats records the handles a registry has handed out. It is
+// guarded by the lock of the registry that owns it.
+type registryStats struct {
+	origins   map[uint64][]uintptr
+	highWater int
+	total     uint64
+}
+
+// added records a new handle along with the call stack that created it,
+// as captured by callers. The stack is only resolved to an export name
+// when diagnostics are asked for.
+func (s *registryStats) added(id uint64, pcs []uintptr) {
+	if s.origins == nil {
+		s.origins = make(map[uint64][]uintptr)
+	}
+	s.origins[id] = pcs
+	s.total++
+	s.highWater = max(s.highWater, len(s.origins))
+}
+
+func (s *registryStats) removed(id uint64) {
+	delete(s.origins, id)
+}
+
+// snapshot copies the stats into their JSON form
+func (s *registryStats) snapshot(name string) registryDiagnostics {
+	d := registryDiagnostics{
+		Name:      name,
+		Live:      len(s.origins),
+		HighWater: s.highWater,
+		Total:     s.total,
+		Handles:   make([]handleDiagnostics, 0, len(s.origins)),
+	}
+	for id, pcs := range s.origins {
+		d.Handles = append(d.Handles, handleDiagnostics{ID: id, Op: exportName(pcs)})
+	}
+	sort.Slice(d.Handles, func(i, j int) bool { return d.Handles[i].ID < d.Handles[j].ID })
+	return d
+}
+
+// funcPrefix is how functions of this package are named on the stack:
+// "main." in the library and the import path when built for tests
+var funcPrefix = strings.TrimSuffix(runtime.FuncForPC(reflect.ValueOf(callers).Pointer()).Name(), "callers")
+
+// callers captures the program counters of its caller's stack without
+// resolving them, which is cheap enough to do on every allocation
+func callers() []uintptr {
+	var pcs [64]uintptr
+	n := runtime.Callers(2, pcs[:])
+	return append([]uintptr(nil), pcs[:n]...)
+}
+
+// exportName names the outermost function of this package on a stack
+// captured by callers, which for calls coming from C is the export that
+// was called
+func exportName(pcs []uintptr) string {
+	frames := runtime.CallersFrames(pcs)
+	op := "unknown"
+	for {
+		frame, more := frames.Next()
+		if name, ok := strings.CutPrefix(frame.Function, funcPrefix); ok {
+			if strings.HasPrefix(name, "_cgoexp") {
+				break
+			}
+			op = funcName(name)
+		}
+		if !more {
+			break
+		}
+	}
+	return op
+}
+
+// funcName trims closure suffixes from a function name, keeping the
+// receiver type of methods: "(*T).M.func1" becomes "T.M"
+func funcName(name string) string {
+	if recv, rest, ok := strings.Cut(name, ")."); ok && strings.HasPrefix(name, "(") {
+		method, _, _ := strings.Cut(rest, ".")
+		return strings.TrimLeft(recv, "(*") + "." + method
+	}
+	name, _, _ = strings.Cut(name, ".")
+	return name
+}
+
+type handleDiagnostics struct {
+	ID uint64 `json:"id"`
+	Op string `json:"op"`
+}
+
+type registryDiagnostics struct {
+	Name      string              `json:"name"`
+	Live      int                 `json:"live"`
+	HighWater int                 `json:"high_water"`
+	Total     uint64              `json:"total"`
+	Handles   []handleDiagnostics `json:"handles"`
+}
+
+type categoryDiagnostics struct {
+	Category       string `json:"category"`
+	Live           int    `json:"live"`
+	LiveBytes      int    `json:"live_bytes"`
+	HighWater      int    `json:"high_water"`
+	HighWaterBytes int    `json:"high_water_bytes"`
+	Total          int    `json:"total"`
+	TotalBytes     int    `json:"total_bytes"`
+}
+
+type stringDiagnostics struct {
+	Enabled        bool                  `json:"enabled"`
+	Live           int                   `json:"live"`
+	LiveBytes      int                   `json:"live_bytes"`
+	HighWater      int                   `json:"high_water"`
+	HighWaterBytes int                   `json:"high_water_bytes"`
+	Total          int                   `json:"total"`
+	TotalBytes     int                   `json:"total_bytes"`
+	Categories     []categoryDiagnostics `json:"categories"`
+}
+
+type diagnostics struct {
+	Registries  []registryDiagnostics `json:"registries"`
+	Strings     stringDiagnostics     `json:"strings"`
+	RenderCache renderCacheStats      `json:"render_cache"`
+}
+
+func collectDiagnostics() diagnostics {
+	var d diagnostics
+
+	styleReg.RLock()
+	d.Registries = append(d.Registries, styleReg.stats.snapshot("style"))
+	styleReg.RUnlock()
+	tableReg.RLock()
+	d.Registries = append(d.Registries, tableReg.stats.snapshot("table"))
+	tableReg.RUnlock()
+	treeReg.RLock()
+	d.Registries = append(d.Registries, treeReg.stats.snapshot("tree"))
+	treeReg.RUnlock()
+	listReg.RLock()
+	d.Registries = append(d.Registries, listReg.stats.snapshot("list"))
+	listReg.RUnlock()
+	themeReg.RLock()
+	d.Registries = append(d.Registries, themeReg.stats.snapshot("theme"))
+	themeReg.RUnlock()
+
+	d.RenderCache = renderCache.stats()
+
+	Memory.Lock()
+	defer Memory.Unlock()
+	d.Strings.Enabled = Memory.enabled
+	d.Strings.Live = len(Memory.allocations)
+	d.Strings.LiveBytes = Memory.liveBytes
+	d.Strings.HighWater = Memory.highWater
+	d.Strings.HighWaterBytes = Memory.highWaterBytes
+	d.Strings.Categories = []categoryDiagnostics{}
+	for category, s := range Memory.categories {
+		d.Strings.Total += s.Total
+		d.Strings.TotalBytes += s.TotalBytes
+		d.Strings.Categories = append(d.Strings.Categories, categoryDiagnostics{
+			Category:       category,
+			Live:           s.Live,
+			LiveBytes:      s.LiveBytes,
+			HighWater:      s.HighWater,
+			HighWaterBytes: s.HighWaterBytes,
+			Total:          s.Total,
+			TotalBytes:     s.TotalBytes,
+		})
+	}
+	sort.Slice(d.Strings.Categories, func(i, j int) bool {
+		return d.Strings.Categories[i].Category < d.Strings.Categories[j].Category
+	})
+	return d
+}
+
+// GetDiagnostics reports, as JSON, the live, peak and total handle counts
+// of every registry with the export that created each live handle, the
+// same counters for tracked strings overall and per category, and the
+// render cache counters. The returned string is not counted among the live
+// strings.
+//
+//export GetDiagnostics
+func GetDiagnostics() (ret *C.char) {
+	defer recoverString("GetDiagnostics", &ret, "{}")
+	data, err := json.Marshal(collectDiagnostics())
+	if err != nil {
+		Log(LogLevelError, "GetDiagnostics encoding error: %v", err)
+		return Memory.CString("{}", "diagnostics string")
+	}
+	return Memory.CString(string(data), "diagnostics string")
+}
+
+// GetLiveHandleCount returns the number of style, table, tree, list and
+// theme handles that have not been freed.
+//
+//export GetLiveHandleCount
+func GetLiveHandleCount() C.uint64_t {
+	defer recoverPanic("GetLiveHandleCount")
+	var live int
+	for _, r := range collectDiagnostics().Registries {
+		live += r.Live
+	}
+	return C.uint64_t(live)
+}
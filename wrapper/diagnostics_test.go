@@ -0,0 +1,47 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiagnostics(t *testing.T) {
+	before := GetLiveHandleCount()
+	style := keep(t, NewStyle())
+	table := NewTable()
+	defer FreeTable(table)
+
+	if got := GetLiveHandleCount(); got != before+2 {
+		t.Errorf("GetLiveHandleCount = %d, want %d", got, before+2)
+	}
+
+	held := StyleString(style)
+	defer FreeString(held)
+
+	var d diagnostics
+	if err := json.Unmarshal([]byte(takeString(GetDiagnostics())), &d); err != nil {
+		t.Fatalf("GetDiagnostics is not valid JSON: %v", err)
+	}
+	if len(d.Registries) != 5 {
+		t.Fatalf("GetDiagnostics reports %d registries, want 5", len(d.Registries))
+	}
+
+	found := false
+	for _, h := range d.Registries[0].Handles {
+		if h.ID == uint64(style) {
+			found = true
+			// Called from Go, the outermost function of the package is the test
+			if h.Op != "TestDiagnostics" {
+				t.Errorf("style %#x was created by %q, want TestDiagnostics", style, h.Op)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("GetDiagnostics does not list style %#x", style)
+	}
+	if !d.Strings.Enabled || d.Strings.Live == 0 {
+		t.Errorf("GetDiagnostics strings = %+v", d.Strings)
+	}
+}
@@ -0,0 +1,376 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// The fuzz targets feed arbitrary strings to the exports that take them.
+// Besides not panicking, output must be valid UTF-8 whenever the input is,
+// and layout functions must produce the size they promise. Run one with
+//
+//	go test ./wrapper/ -run '^$' -fuzz FuzzPlace -fuzztime 1m
+
+// fuzzText cuts s at its first NUL, which is where C stops reading it
+func fuzzText(s string) string {
+	s, _, _ = strings.Cut(s, "\x00")
+	return s
+}
+
+// validUTF8 reports whether every input is valid UTF-8. Inputs are checked
+// separately since two invalid halves can join into a valid string.
+func validUTF8(in ...string) bool {
+	for _, s := range in {
+		if !utf8.ValidString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// padsCleanly reports whether every line of s ends outside an escape
+// sequence. Otherwise padding appended to the line is read as part of the
+// unterminated sequence and the layout comes out narrower than requested.
+func padsCleanly(s string) bool {
+	for _, line := range strings.Split(s, "\n") {
+		if !strings.HasSuffix(ansi.Strip(line+" "), " ") {
+			return false
+		}
+	}
+	return true
+}
+
+// checkUTF8 fails the test if an export turned valid input into invalid
+// UTF-8
+func checkUTF8(t *testing.T, op, out string, in ...string) {
+	t.Helper()
+	if validUTF8(in...) && !utf8.ValidString(out) {
+		t.Fatalf("%s(%q) returned invalid UTF-8 %q", op, in, out)
+	}
+}
+
+func FuzzStyleRender(f *testing.F) {
+	f.Add("Hello, World", "#ff0000", uint8(0), uint8(0), true)
+	f.Add("漢字\nline two", "205", uint8(8), uint8(2), false)
+	f.Add("\x1b[31mred\x1b[0m\ttab", "not a color", uint8(3), uint8(1), true)
+	f.Fuzz(func(t *testing.T, text, color string, width, padding uint8, bold bool) {
+		text, color = fuzzText(text), fuzzText(color)
+		id := keep(t, NewStyle())
+		if fg := StyleForeground(id, cString(t, color)); fg != 0 {
+			id = keep(t, fg)
+		}
+		if width > 0 {
+			id = keep(t, StyleWidth(id, cInt(width)))
+		}
+		id = keep(t, StylePadding(id, 0, cInt(padding%8), 0, cInt(padding%8)))
+		if bold {
+			id = keep(t, StyleBold(id, 1))
+		}
+
+		out := render(t, id, text)
+		checkUTF8(t, "StyleRender", out, text)
+
+		buf := cBuffer(t, len(out)+1)
+		var needed cSize
+		if got := StyleRenderInto(id, cString(t, text), buf, cSize(len(out)+1), &needed); got != renderOK {
+			t.Fatalf("StyleRenderInto returned %d for output StyleRender rendered", got)
+		}
+		if got := peekString(buf); got != out {
+			t.Fatalf("StyleRenderInto wrote %q, StyleRender returned %q", got, out)
+		}
+	})
+}
+
+func FuzzMeasureAndJoin(f *testing.F) {
+	f.Add("a\nbb\nccc", "X", 0.5)
+	f.Add("漢字", "\x1b[1mbold\x1b[0m\n\n", 1.0)
+	f.Add("", "", 0.0)
+	f.Fuzz(func(t *testing.T, a, b string, pos float64) {
+		a, b = fuzzText(a), fuzzText(b)
+		ca, cb := cString(t, a), cString(t, b)
+
+		w, h := Size(ca)
+		if w != Width(ca) || h != Height(ca) {
+			t.Fatalf("Size(%q) = %dx%d, Width and Height give %dx%d", a, w, h, Width(ca), Height(ca))
+		}
+
+		horizontal := takeString(JoinHorizontal(cDouble(pos), ca, cb))
+		vertical := takeString(JoinVertical(cDouble(pos), ca, cb))
+		if pos < 0 || pos > 1 || pos != pos {
+			if horizontal != "" || vertical != "" {
+				t.Fatalf("joining at invalid position %v returned output", pos)
+			}
+			return
+		}
+		checkUTF8(t, "JoinHorizontal", horizontal, a, b)
+		checkUTF8(t, "JoinVertical", vertical, a, b)
+		// Invalid UTF-8 has no defined width
+		if !validUTF8(a, b) || !padsCleanly(a) || !padsCleanly(b) {
+			return
+		}
+		if got, want := Height(cString(t, horizontal)), max(h, Height(cb)); got != want {
+			t.Fatalf("JoinHorizontal(%q, %q) is %d lines high, want %d", a, b, got, want)
+		}
+		if got, want := Width(cString(t, vertical)), max(w, Width(cb)); got != want {
+			t.Fatalf("JoinVertical(%q, %q) is %d cells wide, want %d", a, b, got, want)
+		}
+	})
+}
+
+func FuzzPlace(f *testing.F) {
+	f.Add("x", uint8(3), uint8(3), 0.5, 0.5)
+	f.Add("漢字\nab", uint8(10), uint8(1), 1.0, 0.0)
+	f.Add("too wide for the box", uint8(4), uint8(0), 0.25, 0.75)
+	f.Fuzz(func(t *testing.T, s string, width, height uint8, hPos, vPos float64) {
+		s = fuzzText(s)
+		str := cString(t, s)
+		w, h := int(width), int(height)
+
+		placed := takeString(Place(cInt(w), cInt(h), cDouble(hPos), cDouble(vPos), str))
+		if hPos < 0 || hPos > 1 || hPos != hPos || vPos < 0 || vPos > 1 || vPos != vPos {
+			if placed != "" {
+				t.Fatalf("Place at invalid position (%v, %v) returned %q", hPos, vPos, placed)
+			}
+			return
+		}
+		checkUTF8(t, "Place", placed, s)
+		if !utf8.ValidString(s) || !padsCleanly(s) {
+			return
+		}
+
+		// Place never shrinks its input, so the box is at least as large
+		// as the text
+		sw, sh := int(Width(str)), int(Height(str))
+		placedStr := cString(t, placed)
+		if got, want := int(Width(placedStr)), max(w, sw); got != want {
+			t.Fatalf("Width(Place(%d, %d, %q)) = %d, want %d", w, h, s, got, want)
+		}
+		if got, want := int(Height(placedStr)), max(h, sh); got != want {
+			t.Fatalf("Height(Place(%d, %d, %q)) = %d, want %d", w, h, s, got, want)
+		}
+
+		if got, want := int(Width(cString(t, takeString(PlaceHorizontal(cInt(w), cDouble(hPos), str))))), max(w, sw); got != want {
+			t.Fatalf("Width(PlaceHorizontal(%d, %q)) = %d, want %d", w, s, got, want)
+		}
+		if got, want := int(Height(cString(t, takeString(PlaceVertical(cInt(h), cDouble(vPos), str))))), max(h, sh); got != want {
+			t.Fatalf("Height(PlaceVertical(%d, %q)) = %d, want %d", h, s, got, want)
+		}
+	})
+}
+
+func FuzzStyleRunes(f *testing.F) {
+	f.Add("abc", []byte{1})
+	f.Add("漢字かな", []byte{0, 2, 200, 255})
+	f.Add("\x1b[31mred\x1b[0m", []byte{0x80, 3})
+	f.Add("line\none\ttab", []byte{4, 5, 6})
+	f.Fuzz(func(t *testing.T, s string, indices []byte) {
+		s = fuzzText(s)
+		if len(indices) == 0 {
+			return
+		}
+		// Indices may be negative or past the end of the string
+		ints := make([]int, len(indices))
+		for i, b := range indices {
+			ints[i] = int(int8(b))
+		}
+		matched := lipgloss.NewStyle().Bold(true)
+		unmatched := lipgloss.NewStyle().Faint(true)
+
+		out := takeString(StyleRunes(cString(t, s), cInts(t, ints), cInt(len(ints)), unsafe.Pointer(&matched), unsafe.Pointer(&unmatched)))
+		checkUTF8(t, "StyleRunes", out, s)
+		// Styling the runes of printable text only adds escape sequences.
+		// Control characters are not printable: lipgloss expands tabs,
+		// pads lines and counts the runes of escape sequences, which it may
+		// split.
+		if !utf8.ValidString(s) || strings.IndexFunc(s, unicode.IsControl) >= 0 {
+			return
+		}
+		if got := takeString(Strip(cString(t, out))); got != s {
+			t.Fatalf("StyleRunes(%q) stripped is %q", s, got)
+		}
+	})
+}
+
+func FuzzANSI(f *testing.F) {
+	f.Add("\x1b[1;31mHello\x1b[0m, 漢字 world", int8(5), "…")
+	f.Add("\x1b]8;;https://example.com\x07link\x1b]8;;\x07 text", int8(3), "")
+	f.Add("a\tb\r\nc\x1b[", int8(0), ">")
+	f.Add("\x1b[38;2;255;0;0mtruecolor\x1b[48;5;21m256\x1b[m", int8(-1), "")
+	f.Fuzz(func(t *testing.T, s string, n int8, tail string) {
+		s, tail = fuzzText(s), fuzzText(tail)
+		str, ctail := cString(t, s), cString(t, tail)
+
+		stripped := takeString(Strip(str))
+		checkUTF8(t, "Strip", stripped, s)
+		checkUTF8(t, "ANSIToHTML", takeString(ANSIToHTML(str)), s)
+		checkUTF8(t, "RenderToSVG", takeString(RenderToSVG(str, cSVGOptions(t, tail))), s, tail)
+
+		for op, out := range map[string]string{
+			"Truncate":     takeString(Truncate(str, cInt(n), ctail)),
+			"TruncateLeft": takeString(TruncateLeft(str, cInt(n), ctail)),
+			"Wordwrap":     takeString(Wordwrap(str, cInt(n), ctail)),
+			"Hardwrap":     takeString(Hardwrap(str, cInt(n), 1)),
+			"Cut":          takeString(Cut(str, cInt(n), cInt(n)*2)),
+		} {
+			if n < 0 {
+				if out != "" {
+					t.Fatalf("%s(%q, %d) = %q, want empty", op, s, n, out)
+				}
+				continue
+			}
+			checkUTF8(t, op, out, s, tail)
+		}
+		if n < 0 || !validUTF8(s, tail) {
+			return
+		}
+
+		limit := int(n)
+		if tailWidth := int(Width(ctail)); tailWidth <= limit {
+			if got := int(Width(cString(t, takeString(Truncate(str, cInt(n), ctail))))); got > limit {
+				t.Fatalf("Truncate(%q, %d, %q) is %d cells wide", s, n, tail, got)
+			}
+		}
+		if got := int(Width(cString(t, takeString(Cut(str, cInt(n), cInt(n)*2))))); got > limit {
+			t.Fatalf("Cut(%q, %d, %d) is %d cells wide", s, n, 2*n, got)
+		}
+	})
+}
+
+func FuzzColor(f *testing.F) {
+	f.Add("#ff8800", "#000", 0.5)
+	f.Add("#abc", "205", 0.0)
+	f.Add("21", "#12345", 1.5)
+	f.Add("rgb(1,2,3)", "", -0.25)
+	f.Fuzz(func(t *testing.T, a, b string, amount float64) {
+		a, b = fuzzText(a), fuzzText(b)
+		ca, cb := cString(t, a), cString(t, b)
+
+		valid := Validate.Color(a, "fuzz") == nil
+		ColorRGBA(ca)
+		AdaptiveColorRGBA(ca, cb)
+		CompleteColorRGBA(ca, cb, cb)
+		if id := StyleForeground(keep(t, NewStyle()), ca); id != 0 {
+			FreeStyle(id)
+		} else if valid {
+			t.Fatalf("StyleForeground rejected %q, which Validate.Color accepts", a)
+		}
+
+		for op, out := range map[string]string{
+			"ColorLighten":  takeString(ColorLighten(ca, cDouble(amount))),
+			"ColorDarken":   takeString(ColorDarken(ca, cDouble(amount))),
+			"ColorSaturate": takeString(ColorSaturate(ca, cDouble(amount))),
+			"BlendColor":    takeString(BlendColor(ca, cb, cDouble(amount))),
+		} {
+			if out != "" && !isHexColor(out) {
+				t.Fatalf("%s(%q) = %q, want a hex color or empty", op, a, out)
+			}
+		}
+		if got := ColorToANSI256(ca); got < -1 || got > 255 {
+			t.Fatalf("ColorToANSI256(%q) = %d", a, got)
+		}
+		if got := ColorToANSI16(ca); got < -1 || got > 15 {
+			t.Fatalf("ColorToANSI16(%q) = %d", a, got)
+		}
+		if ratio := ColorContrastRatio(ca, cb); ratio != 0 && (ratio < 1 || ratio > 21) {
+			t.Fatalf("ColorContrastRatio(%q, %q) = %v", a, b, ratio)
+		}
+	})
+}
+
+// isHexColor reports whether s is a #rrggbb color
+func isHexColor(s string) bool {
+	if len(s) != 7 || s[0] != '#' {
+		return false
+	}
+	return strings.Trim(s[1:], "0123456789abcdefABCDEF") == ""
+}
+
+func FuzzLoadTheme(f *testing.F) {
+	f.Add(themeJSON, uint8(0))
+	f.Add("[styles.base]\nforeground = \"#eeeeee\"\npadding = [0, 1]\n", uint8(2))
+	f.Add("styles:\n  base: {bold: true, border: rounded}\n  h1: {inherit: base}\n", uint8(3))
+	f.Add(`{"styles": {"a": {"inherit": "b"}, "b": {"inherit": "a"}}}`, uint8(1))
+	f.Fuzz(func(t *testing.T, source string, format uint8) {
+		var errOut *cChar
+		id := LoadTheme(cString(t, fuzzText(source)), cInt(format%5), &errOut)
+		if id == 0 {
+			if errOut == nil {
+				t.Fatal("LoadTheme failed without an error message")
+			}
+			checkUTF8(t, "LoadTheme error", takeString(errOut), source)
+			return
+		}
+		defer FreeTheme(id)
+		if errOut != nil {
+			t.Fatalf("LoadTheme succeeded with an error message: %s", takeString(errOut))
+		}
+		// A loaded theme must be usable for rendering
+		checkUTF8(t, "RenderMarkdown", takeString(RenderMarkdown(cString(t, "# Title\n\n- item `code`"), id)), source)
+	})
+}
+
+func FuzzStyleFromCSS(f *testing.F) {
+	f.Add("color: #ff0; padding: 1 2; font-weight: bold; border: rounded")
+	f.Add("margin: 0 1 2 3; text-align: center; width: 10")
+	f.Add("background-color: 205;; ; unknown: x")
+	f.Fuzz(func(t *testing.T, css string) {
+		var errOut *cChar
+		id := StyleFromCSS(cString(t, fuzzText(css)), &errOut)
+		if id == 0 {
+			if errOut == nil {
+				t.Fatal("StyleFromCSS failed without an error message")
+			}
+			FreeString(errOut)
+			return
+		}
+		keep(t, id)
+		checkUTF8(t, "StyleRender", render(t, id, "text"), css)
+	})
+}
+
+func FuzzStyleFromJSON(f *testing.F) {
+	f.Add(`{"foreground": "#eeeeee", "padding": [0, 1], "bold": true}`)
+	f.Add(`{"border": "rounded", "width": 10, "align": "center"}`)
+	f.Add(`{"margin": [1, 2, 3, 4], "inherit": "missing"}`)
+	f.Fuzz(func(t *testing.T, data string) {
+		var errOut *cChar
+		id := StyleFromJSON(cString(t, fuzzText(data)), &errOut)
+		if id == 0 {
+			if errOut == nil {
+				t.Fatal("StyleFromJSON failed without an error message")
+			}
+			FreeString(errOut)
+			return
+		}
+		keep(t, id)
+
+		// Serializing is a fixed point after one round trip
+		first := takeString(StyleToJSON(id))
+		again := StyleFromJSON(cString(t, first), &errOut)
+		if again == 0 {
+			t.Fatalf("StyleFromJSON cannot read back %s: %s", first, takeString(errOut))
+		}
+		keep(t, again)
+		if second := takeString(StyleToJSON(again)); second != first {
+			t.Fatalf("StyleToJSON round trip changed\n%s\nto\n%s", first, second)
+		}
+	})
+}
+
+func FuzzRenderMarkdown(f *testing.F) {
+	f.Add("# Title\n\nSome *emphasis* and `code`.\n\n- one\n- two\n")
+	f.Add("> quote\n\n```go\nfunc main() {}\n```\n\n| a | b |\n|---|---|\n| 1 | 2 |\n")
+	f.Add("[link](http://example.com) ![img](x.png) <b>html</b>")
+	f.Fuzz(func(t *testing.T, md string) {
+		md = fuzzText(md)
+		checkUTF8(t, "RenderMarkdown", takeString(RenderMarkdown(cString(t, md), 0)), md)
+	})
+}
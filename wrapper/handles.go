@@ -0,0 +1,295 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"sync"
+)
+
+// handleKind tells which registry a handle belongs to
+type handleKind uint8
+
+const (
+	handleKindInvalid handleKind = C.HANDLE_KIND_INVALID
+	handleKindStyle   handleKind = C.HANDLE_KIND_STYLE
+	handleKindTable   handleKind = C.HANDLE_KIND_TABLE
+	handleKindTree    handleKind = C.HANDLE_KIND_TREE
+	handleKindList    handleKind = C.HANDLE_KIND_LIST
+	handleKindTheme   handleKind = C.HANDLE_KIND_THEME
+)
+
+func (k handleKind) String() string {
+	switch k {
+	case handleKindStyle:
+		return "style"
+	case handleKindTable:
+		return "table"
+	case handleKindTree:
+		return "tree"
+	case handleKindList:
+		return "list"
+	case handleKindTheme:
+		return "theme"
+	}
+	return "invalid"
+}
+
+// A handle packs the registry kind into the top 8 bits, the generation of
+// its slot into the next 24 and the slot index into the low 32. Freeing a
+// handle bumps the slot's generation, so stale copies stop matching even
+// after the slot is reused.
+const (
+	handleKindShift = 56
+	handleGenShift  = 32
+	handleGenMask   = 1<<24 - 1
+	handleSlotMask  = 1<<32 - 1
+)
+
+func makeHandle(kind handleKind, gen uint32, slot uint32) uint64 {
+	return uint64(kind)<<handleKindShift | uint64(gen&handleGenMask)<<handleGenShift | uint64(slot)
+}
+
+func splitHandle(h uint64) (kind handleKind, gen uint32, slot uint32) {
+	return handleKind(h >> handleKindShift), uint32(h>>handleGenShift) & handleGenMask, uint32(h & handleSlotMask)
+}
+
+// handleSlot holds one registered value and the generation of its handle
+type handleSlot[T any] struct {
+	gen   uint32
+	value *T
+}
+
+// handleRegistry hands out generational handles for values of one kind
+type handleRegistry[T any] struct {
+	sync.RWMutex
+	kind  handleKind
+	slots []handleSlot[T]
+	free  []uint32
+	stats registryStats
+}
+
+// Register stores v and returns its handle, or 0 if v is nil
+func (r *handleRegistry[T]) Register(v *T) uint64 {
+	if v == nil {
+		Log(LogLevelError, "Attempted to register nil %s", r.kind)
+		return 0
+	}
+	origin := callers()
+
+	r.Lock()
+	defer r.Unlock()
+
+	var slot uint32
+	if n := len(r.free); n > 0 {
+		slot = r.free[n-1]
+		r.free = r.free[:n-1]
+	} else {
+		slot = uint32(len(r.slots))
+		r.slots = append(r.slots, handleSlot[T]{gen: 1})
+	}
+	r.slots[slot].value = v
+	id := makeHandle(r.kind, r.slots[slot].gen, slot)
+	r.stats.added(id, origin)
+	scopes.adoptHandle(id)
+	Log(LogLevelDebug, "Registered new %s with handle %#x", r.kind, id)
+	return id
+}
+
+// handleProblem tells why a handle cannot be used
+type handleProblem uint8
+
+const (
+	handleUsable handleProblem = iota
+	handleNull
+	handleNotAHandle
+	handleWrongKind
+	handleNeverAllocated
+	handleFreed
+)
+
+// check returns the slot a handle refers to, or the reason the handle is
+// unusable. It does no allocation, so lookups of live handles stay cheap.
+// The caller holds the lock.
+func (r *handleRegistry[T]) check(id uint64) (*handleSlot[T], handleProblem) {
+	if id == 0 {
+		return nil, handleNull
+	}
+	kind, gen, slot := splitHandle(id)
+	if kind == handleKindInvalid || kind > handleKindTheme {
+		return nil, handleNotAHandle
+	}
+	if kind != r.kind {
+		return nil, handleWrongKind
+	}
+	if int(slot) >= len(r.slots) {
+		return nil, handleNeverAllocated
+	}
+	s := &r.slots[slot]
+	if s.gen != gen || s.value == nil {
+		return nil, handleFreed
+	}
+	return s, handleUsable
+}
+
+// error describes a problem found by check. freed describes a handle that
+// was already freed.
+func (r *handleRegistry[T]) error(id uint64, op string, problem handleProblem, freed string) error {
+	var msg string
+	switch problem {
+	case handleNull:
+		msg = fmt.Sprintf("null %s handle", r.kind)
+	case handleNotAHandle:
+		msg = fmt.Sprintf("not a valid handle, expected a %s handle", r.kind)
+	case handleWrongKind:
+		kind, _, _ := splitHandle(id)
+		msg = fmt.Sprintf("expected a %s handle, got a %s handle", r.kind, kind)
+	case handleNeverAllocated:
+		msg = fmt.Sprintf("%s handle was never allocated", r.kind)
+	default:
+		msg = fmt.Sprintf(freed, r.kind)
+	}
+	return &RegistryError{Op: op, ID: id, Message: msg}
+}
+
+// Lookup returns the value behind a handle, or an error naming op
+func (r *handleRegistry[T]) Lookup(id uint64, op string) (*T, error) {
+	r.RLock()
+	defer r.RUnlock()
+
+	s, problem := r.check(id)
+	if problem != handleUsable {
+		return nil, r.error(id, op, problem, "use of freed %s handle")
+	}
+	return s.value, nil
+}
+
+// LookupAll resolves many handles under a single read lock. errs[i] is
+// non-nil for every handle that could not be resolved.
+func (r *handleRegistry[T]) LookupAll(ids []uint64, op string) (values []*T, errs []error) {
+	values = make([]*T, len(ids))
+	errs = make([]error, len(ids))
+
+	r.RLock()
+	defer r.RUnlock()
+	for i, id := range ids {
+		if s, problem := r.check(id); problem != handleUsable {
+			errs[i] = r.error(id, op, problem, "use of freed %s handle")
+		} else {
+			values[i] = s.value
+		}
+	}
+	return values, errs
+}
+
+// Get returns the value behind a handle, logging why and returning nil if
+// the handle is unusable. The export named in the log is only looked up
+// when there is something to log.
+func (r *handleRegistry[T]) Get(id uint64) *T {
+	r.RLock()
+	s, problem := r.check(id)
+	var v *T
+	if s != nil {
+		v = s.value
+	}
+	r.RUnlock()
+
+	if problem != handleUsable {
+		Log(LogLevelError, "%v", r.error(id, exportName(callers()), problem, "use of freed %s handle"))
+	}
+	return v
+}
+
+// Remove frees a handle and returns the value it referred to. Freeing a
+// handle twice is reported as an error.
+func (r *handleRegistry[T]) Remove(id uint64) *T {
+	r.Lock()
+	defer r.Unlock()
+
+	s, problem := r.check(id)
+	if problem != handleUsable {
+		Log(LogLevelError, "%v", r.error(id, exportName(callers()), problem, "double free of %s handle"))
+		return nil
+	}
+
+	v := s.value
+	s.value = nil
+	s.gen = (s.gen + 1) & handleGenMask
+	if s.gen == 0 {
+		s.gen = 1
+	}
+	_, _, slot := splitHandle(id)
+	r.free = append(r.free, slot)
+	r.stats.removed(id)
+	Log(LogLevelDebug, "Removed %s with handle %#x", r.kind, id)
+	return v
+}
+
+// Len returns the number of live handles
+func (r *handleRegistry[T]) Len() int {
+	r.RLock()
+	defer r.RUnlock()
+	return len(r.slots) - len(r.free)
+}
+
+// checkHandle validates a handle of any kind
+func checkHandle(id uint64, op string) error {
+	kind, _, _ := splitHandle(id)
+	switch kind {
+	case handleKindStyle:
+		_, err := styleReg.Lookup(id, op)
+		return err
+	case handleKindTable:
+		_, err := tableReg.Lookup(id, op)
+		return err
+	case handleKindTree:
+		_, err := treeReg.Lookup(id, op)
+		return err
+	case handleKindList:
+		_, err := listReg.Lookup(id, op)
+		return err
+	case handleKindTheme:
+		_, err := themeReg.Lookup(id, op)
+		return err
+	}
+	return &RegistryError{Op: op, ID: id, Message: "not a handle"}
+}
+
+// HandleKind returns the CHandleKind encoded in a handle, without checking
+// that the handle is live.
+//
+//export HandleKind
+func HandleKind(id C.uint64_t) C.int {
+	defer recoverPanic("HandleKind")
+	kind, _, _ := splitHandle(uint64(id))
+	if kind > handleKindTheme {
+		return C.int(handleKindInvalid)
+	}
+	return C.int(kind)
+}
+
+// HandleIsValid returns 1 if id is a live handle of any kind and 0
+// otherwise, logging nothing.
+//
+//export HandleIsValid
+func HandleIsValid(id C.uint64_t) C.int {
+	defer recoverPanic("HandleIsValid")
+	return String.ToCInt(checkHandle(uint64(id), "handle-is-valid") == nil)
+}
+
+// HandleError describes why a handle is unusable, or returns an empty
+// string for a live handle.
+//
+//export HandleError
+func HandleError(id C.uint64_t) (ret *C.char) {
+	defer recoverString("HandleError", &ret, "")
+	msg := ""
+	if err := checkHandle(uint64(id), "handle-error"); err != nil {
+		msg = err.Error()
+	}
+	return Memory.CString(msg, "HandleError result")
+}
@@ -0,0 +1,107 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHandleKinds(t *testing.T) {
+	style := keep(t, NewStyle())
+	table := NewTable()
+	defer FreeTable(table)
+	tree := NewTree()
+	defer FreeTree(tree)
+	list := NewList()
+	defer FreeList(list)
+	theme := NewTheme()
+	defer FreeTheme(theme)
+
+	for _, tc := range []struct {
+		id   cHandle
+		want handleKind
+	}{
+		{style, handleKindStyle},
+		{table, handleKindTable},
+		{tree, handleKindTree},
+		{list, handleKindList},
+		{theme, handleKindTheme},
+		{0, handleKindInvalid},
+		{cHandle(0xff) << handleKindShift, handleKindInvalid},
+	} {
+		if got := HandleKind(tc.id); got != cInt(tc.want) {
+			t.Errorf("HandleKind(%#x) = %d, want %s", tc.id, got, tc.want)
+		}
+	}
+	if HandleIsValid(table) != 1 || HandleIsValid(stale()) != 0 || HandleIsValid(0) != 0 {
+		t.Error("HandleIsValid does not tell live handles from others")
+	}
+}
+
+func TestHandleErrors(t *testing.T) {
+	table := NewTable()
+	defer FreeTable(table)
+
+	if got := takeString(HandleError(table)); got != "" {
+		t.Errorf("HandleError of a live handle = %q, want empty", got)
+	}
+	for _, tc := range []struct {
+		id   cHandle
+		want string
+	}{
+		{stale(), "use of freed style handle"},
+		{0, "not a handle"},
+		{cHandle(makeHandle(handleKindList, 1, 1<<20)), "list handle was never allocated"},
+	} {
+		if got := takeString(HandleError(tc.id)); !strings.Contains(got, tc.want) {
+			t.Errorf("HandleError(%#x) = %q, want it to contain %q", tc.id, got, tc.want)
+		}
+	}
+
+	// A handle of the wrong kind is rejected instead of aliasing a style
+	expectLog(t, "expected a style handle, got a table handle", func() {
+		if got := render(t, table, "x"); got != "" {
+			t.Errorf("rendering a table handle as a style = %q", got)
+		}
+	})
+}
+
+func TestHandleReuse(t *testing.T) {
+	first := NewStyle()
+	FreeStyle(first)
+	second := keep(t, NewStyle())
+
+	_, _, slot1 := splitHandle(uint64(first))
+	_, _, slot2 := splitHandle(uint64(second))
+	if slot1 != slot2 {
+		t.Skip("the freed slot was not reused")
+	}
+	if first == second {
+		t.Fatal("a reused slot handed out the freed handle again")
+	}
+	expectLog(t, "use of freed style handle", func() { StyleGetBold(first) })
+}
+
+func TestHandleGetDoesNotAllocate(t *testing.T) {
+	style := uint64(keep(t, NewStyle()))
+	if n := testing.AllocsPerRun(100, func() { styleReg.Get(style) }); n != 0 {
+		t.Errorf("looking up a live handle allocates %v times", n)
+	}
+}
+
+func BenchmarkHandleGet(b *testing.B) {
+	style := NewStyle()
+	defer FreeStyle(style)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		styleReg.Get(uint64(style))
+	}
+}
+
+func BenchmarkHandleRegister(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FreeStyle(NewStyle())
+	}
+}
@@ -13,7 +13,8 @@ import (
 )
 
 //export ColorProfile
-func ColorProfile() *C.char {
+func ColorProfile() (ret *C.char) {
+	defer recoverString("ColorProfile", &ret, "ascii")
 	profile := lipgloss.ColorProfile()
 	var profileStr string
 	switch profile {
@@ -32,8 +33,7 @@ func ColorProfile() *C.char {
 	cs, err := String.CString(profileStr)
 	if err != nil {
 		Log(LogLevelError, "ColorProfile memory allocation error: %v", err)
-		defaultCs, _ := String.CString("ascii") // Safe fallback
-		return defaultCs
+		return Memory.CString("ascii", "ColorProfile result")
 	}
 	Memory.Track(unsafe.Pointer(cs), "ColorProfile result")
 	return cs
@@ -41,33 +41,41 @@ func ColorProfile() *C.char {
 
 //export HasDarkBackground
 func HasDarkBackground() C.bool {
+	defer recoverPanic("HasDarkBackground")
 	return C.bool(lipgloss.HasDarkBackground())
 }
 
 //export Height
 func Height(str *C.char) C.int {
+	defer recoverPanic("Height")
 	goStr := String.GoString(str)
 	height := lipgloss.Height(goStr)
 	Log(LogLevelDebug, "Calculated height %d for string", height)
 	return C.int(height)
 }
 
-//export JoinHorizontal
-func JoinHorizontal(pos C.double, str1 *C.char, str2 *C.char) *C.char {
-	goStr1 := String.GoString(str1)
-	goStr2 := String.GoString(str2)
+// joinHorizontal validates pos and joins two blocks side by side
+func joinHorizontal(pos float64, str1, str2 string) (string, error) {
+	if err := Validate.Position(pos, "horizontal"); err != nil {
+		return "", err
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Position(pos), str1, str2), nil
+}
 
-	if err := Validate.Position(float64(pos), "horizontal"); err != nil {
+//export JoinHorizontal
+func JoinHorizontal(pos C.double, str1 *C.char, str2 *C.char) (ret *C.char) {
+	defer recoverString("JoinHorizontal", &ret, "")
+	joined, err := joinHorizontal(float64(pos), String.GoString(str1), String.GoString(str2))
+	if err != nil {
 		Log(LogLevelError, "JoinHorizontal position error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "JoinHorizontal result")
 		return defaultCs
 	}
 
-	joined := lipgloss.JoinHorizontal(lipgloss.Position(pos), goStr1, goStr2)
 	cs, err := String.CString(joined)
 	if err != nil {
 		Log(LogLevelError, "JoinHorizontal memory allocation error: %v", err)
-		defaultCs, _ := String.CString("") // Safe fallback
+		defaultCs := Memory.CString("", "JoinHorizontal result") // Safe fallback
 		return defaultCs
 	}
 
@@ -76,7 +84,14 @@ func JoinHorizontal(pos C.double, str1 *C.char, str2 *C.char) *C.char {
 }
 
 //export JoinVertical
-func JoinVertical(pos C.double, str1 *C.char, str2 *C.char) *C.char {
+func JoinVertical(pos C.double, str1 *C.char, str2 *C.char) (ret *C.char) {
+	defer recoverString("JoinVertical", &ret, "")
+	if err := Validate.Position(float64(pos), "vertical"); err != nil {
+		Log(LogLevelError, "JoinVertical position error: %v", err)
+		defaultCs := Memory.CString("", "JoinVertical result")
+		return defaultCs
+	}
+
 	goStr1 := String.GoString(str1)
 	goStr2 := String.GoString(str2)
 
@@ -84,7 +99,7 @@ func JoinVertical(pos C.double, str1 *C.char, str2 *C.char) *C.char {
 	cs, err := String.CString(joined)
 	if err != nil {
 		Log(LogLevelError, "JoinVertical memory allocation error: %v", err)
-		defaultCs, _ := String.CString("") // Safe fallback
+		defaultCs := Memory.CString("", "JoinVertical result") // Safe fallback
 		return defaultCs
 	}
 
@@ -92,27 +107,37 @@ func JoinVertical(pos C.double, str1 *C.char, str2 *C.char) *C.char {
 	return cs
 }
 
-//export Place
-func Place(width, height C.int, hPos, vPos C.double, str *C.char) *C.char {
-	if err := Validate.Dimension(int(width), "width"); err != nil {
-		Log(LogLevelError, "Place width validation error: %v", err)
-		defaultCs, _ := String.CString("")
-		return defaultCs
+// place validates the box size and positions, and positions str inside it
+func place(width, height int, hPos, vPos float64, str string) (string, error) {
+	if err := Validate.Dimension(width, "width"); err != nil {
+		return "", err
 	}
-	if err := Validate.Dimension(int(height), "height"); err != nil {
-		Log(LogLevelError, "Place height validation error: %v", err)
-		defaultCs, _ := String.CString("")
-		return defaultCs
+	if err := Validate.Dimension(height, "height"); err != nil {
+		return "", err
+	}
+	if err := Validate.Position(hPos, "horizontal"); err != nil {
+		return "", err
 	}
+	if err := Validate.Position(vPos, "vertical"); err != nil {
+		return "", err
+	}
+	return lipgloss.Place(width, height, lipgloss.Position(hPos), lipgloss.Position(vPos), str), nil
+}
 
-	goStr := String.GoString(str)
-	placed := lipgloss.Place(int(width), int(height),
-		lipgloss.Position(hPos), lipgloss.Position(vPos), goStr)
+//export Place
+func Place(width, height C.int, hPos, vPos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("Place", &ret, "")
+	placed, err := place(int(width), int(height), float64(hPos), float64(vPos), String.GoString(str))
+	if err != nil {
+		Log(LogLevelError, "Place validation error: %v", err)
+		defaultCs := Memory.CString("", "Place result")
+		return defaultCs
+	}
 
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "Place memory allocation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "Place result")
 		return defaultCs
 	}
 
@@ -121,10 +146,16 @@ func Place(width, height C.int, hPos, vPos C.double, str *C.char) *C.char {
 }
 
 //export PlaceHorizontal
-func PlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
+func PlaceHorizontal(width C.int, pos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("PlaceHorizontal", &ret, "")
 	if err := Validate.Dimension(int(width), "width"); err != nil {
 		Log(LogLevelError, "PlaceHorizontal width validation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "PlaceHorizontal result")
+		return defaultCs
+	}
+	if err := Validate.Position(float64(pos), "horizontal"); err != nil {
+		Log(LogLevelError, "PlaceHorizontal position error: %v", err)
+		defaultCs := Memory.CString("", "PlaceHorizontal result")
 		return defaultCs
 	}
 
@@ -134,7 +165,7 @@ func PlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "PlaceHorizontal memory allocation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "PlaceHorizontal result")
 		return defaultCs
 	}
 
@@ -143,10 +174,16 @@ func PlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
 }
 
 //export PlaceVertical
-func PlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
+func PlaceVertical(height C.int, pos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("PlaceVertical", &ret, "")
 	if err := Validate.Dimension(int(height), "height"); err != nil {
 		Log(LogLevelError, "PlaceVertical height validation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "PlaceVertical result")
+		return defaultCs
+	}
+	if err := Validate.Position(float64(pos), "vertical"); err != nil {
+		Log(LogLevelError, "PlaceVertical position error: %v", err)
+		defaultCs := Memory.CString("", "PlaceVertical result")
 		return defaultCs
 	}
 
@@ -156,7 +193,7 @@ func PlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "PlaceVertical memory allocation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "PlaceVertical result")
 		return defaultCs
 	}
 
@@ -166,6 +203,7 @@ func PlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
 
 //export SetColorProfile
 func SetColorProfile(profile *C.char) {
+	defer recoverPanic("SetColorProfile")
 	profileStr := String.GoString(profile)
 	var termProfile termenv.Profile
 
@@ -189,12 +227,14 @@ func SetColorProfile(profile *C.char) {
 
 //export SetHasDarkBackground
 func SetHasDarkBackground(b C.bool) {
+	defer recoverPanic("SetHasDarkBackground")
 	lipgloss.SetHasDarkBackground(bool(b))
 	Log(LogLevelDebug, "Set dark background to: %v", bool(b))
 }
 
 //export Size
 func Size(str *C.char) (C.int, C.int) {
+	defer recoverPanic("Size")
 	goStr := String.GoString(str)
 	width, height := lipgloss.Size(goStr)
 	Log(LogLevelDebug, "Calculated size: width=%d, height=%d", width, height)
@@ -203,11 +243,17 @@ func Size(str *C.char) (C.int, C.int) {
 
 //export StyleRunes
 func StyleRunes(str *C.char, indices *C.int, indicesLen C.int,
-	matchedHandle, unmatchedHandle unsafe.Pointer) *C.char {
+	matchedHandle, unmatchedHandle unsafe.Pointer) (ret *C.char) {
+	defer recoverString("StyleRunes", &ret, "")
 
 	if indices == nil || indicesLen <= 0 {
 		Log(LogLevelError, "StyleRunes received invalid indices")
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "StyleRunes result")
+		return defaultCs
+	}
+	if matchedHandle == nil || unmatchedHandle == nil {
+		Log(LogLevelError, "StyleRunes received nil style")
+		defaultCs := Memory.CString("", "StyleRunes result")
 		return defaultCs
 	}
 
@@ -226,7 +272,7 @@ func StyleRunes(str *C.char, indices *C.int, indicesLen C.int,
 	cs, err := String.CString(styled)
 	if err != nil {
 		Log(LogLevelError, "StyleRunes memory allocation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "StyleRunes result")
 		return defaultCs
 	}
 
@@ -236,6 +282,7 @@ func StyleRunes(str *C.char, indices *C.int, indicesLen C.int,
 
 //export Width
 func Width(str *C.char) C.int {
+	defer recoverPanic("Width")
 	goStr := String.GoString(str)
 	width := lipgloss.Width(goStr)
 	Log(LogLevelDebug, "Calculated width %d for string", width)
@@ -0,0 +1,273 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestMain(m *testing.M) {
+	flag.Parse()
+
+	// Golden outputs depend on the color profile and background, so pin
+	// them instead of detecting the terminal running the tests
+	lipgloss.SetColorProfile(termenv.TrueColor)
+	lipgloss.SetHasDarkBackground(true)
+	log.SetOutput(io.Discard)
+
+	code := m.Run()
+	if code == 0 {
+		code = checkNothingLive()
+	}
+	os.Exit(code)
+}
+
+// checkNothingLive reports handles and strings the tests forgot to free
+func checkNothingLive() int {
+	d := collectDiagnostics()
+	leaked := d.Strings.Live
+	for _, r := range d.Registries {
+		leaked += r.Live
+	}
+	if leaked == 0 {
+		return 0
+	}
+	fmt.Fprintf(os.Stderr, "tests leaked %d handles and strings:\n", leaked)
+	for _, r := range d.Registries {
+		for _, h := range r.Handles {
+			fmt.Fprintf(os.Stderr, "  %s %#x from %s\n", r.Name, h.ID, h.Op)
+		}
+	}
+	for _, c := range d.Strings.Categories {
+		if c.Live > 0 {
+			fmt.Fprintf(os.Stderr, "  %d strings of %q\n", c.Live, c.Category)
+		}
+	}
+	return 1
+}
+
+// logged runs fn and returns what it logged
+func logged(fn func()) string {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(io.Discard)
+	fn()
+	return buf.String()
+}
+
+// expectLog fails the test unless fn logs a message containing want
+func expectLog(t *testing.T, want string, fn func()) {
+	t.Helper()
+	if got := logged(fn); !strings.Contains(got, want) {
+		t.Errorf("expected a log message containing %q, got %q", want, got)
+	}
+}
+
+// golden compares got with testdata/<name>.golden, or rewrites the file
+// when the tests run with -update
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run the tests with -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output does not match %s\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// keep fails the test if a constructor or setter returned 0, and frees the
+// style it returned once the test ends
+func keep(t testing.TB, id cHandle) cHandle {
+	t.Helper()
+	if id == 0 {
+		t.Fatal("expected a style handle, got 0")
+	}
+	t.Cleanup(func() { FreeStyle(id) })
+	return id
+}
+
+// render renders s with a style and frees the result
+func render(t testing.TB, id cHandle, s string) string {
+	return takeString(StyleRender(id, cString(t, s)))
+}
+
+// stale returns a style handle that has already been freed
+func stale() cHandle {
+	id := NewStyle()
+	FreeStyle(id)
+	return id
+}
+
+func TestEveryExportIsTested(t *testing.T) {
+	sources, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var code, tests strings.Builder
+	for _, path := range sources {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.HasSuffix(path, "_test.go") {
+			tests.Write(data)
+		} else {
+			code.Write(data)
+		}
+	}
+
+	exports := regexp.MustCompile(`(?m)^//export (\w+)$`).FindAllStringSubmatch(code.String(), -1)
+	if len(exports) == 0 {
+		t.Fatal("found no exports")
+	}
+	for _, export := range exports {
+		if !regexp.MustCompile(`\b` + export[1] + `\s*[(,)}]`).MatchString(tests.String()) {
+			t.Errorf("export %s is not called by any test", export[1])
+		}
+	}
+}
+
+func TestColorProfileAndBackground(t *testing.T) {
+	if got := takeString(ColorProfile()); got != "truecolor" {
+		t.Errorf("ColorProfile() = %q, want truecolor", got)
+	}
+	if !HasDarkBackground() {
+		t.Error("HasDarkBackground() = false, want true")
+	}
+
+	t.Cleanup(func() {
+		lipgloss.SetColorProfile(termenv.TrueColor)
+		lipgloss.SetHasDarkBackground(true)
+	})
+	SetColorProfile(cString(t, "ansi256"))
+	if got := takeString(ColorProfile()); got != "ansi256" {
+		t.Errorf("ColorProfile() after SetColorProfile(ansi256) = %q", got)
+	}
+	expectLog(t, "invalid profile", func() { SetColorProfile(cString(t, "sepia")) })
+	if got := takeString(ColorProfile()); got != "ansi256" {
+		t.Errorf("an invalid profile changed ColorProfile() to %q", got)
+	}
+
+	SetHasDarkBackground(false)
+	if HasDarkBackground() {
+		t.Error("HasDarkBackground() = true after SetHasDarkBackground(false)")
+	}
+}
+
+func TestMeasure(t *testing.T) {
+	block := "ab\n漢字漢\nc"
+	if got := Width(cString(t, block)); got != 6 {
+		t.Errorf("Width = %d, want 6", got)
+	}
+	if got := Height(cString(t, block)); got != 3 {
+		t.Errorf("Height = %d, want 3", got)
+	}
+	if w, h := Size(cString(t, block)); w != 6 || h != 3 {
+		t.Errorf("Size = %dx%d, want 6x3", w, h)
+	}
+	if got := Width(nil); got != 0 {
+		t.Errorf("Width(NULL) = %d, want 0", got)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	left, right := cString(t, "a\nb\nc"), cString(t, "X")
+
+	if got := takeString(JoinHorizontal(1, left, right)); got != "a \nb \ncX" {
+		t.Errorf("JoinHorizontal(bottom) = %q", got)
+	}
+	if got := takeString(JoinVertical(1, left, right)); got != "a\nb\nc\nX" {
+		t.Errorf("JoinVertical(right) = %q", got)
+	}
+
+	for name, fn := range map[string]func() *cChar{
+		"JoinHorizontal": func() *cChar { return JoinHorizontal(1.5, left, right) },
+		"JoinVertical":   func() *cChar { return JoinVertical(cDouble(math.NaN()), left, right) },
+	} {
+		var got string
+		expectLog(t, name+" position error", func() { got = takeString(fn()) })
+		if got != "" {
+			t.Errorf("%s with an invalid position = %q, want empty", name, got)
+		}
+	}
+}
+
+func TestPlace(t *testing.T) {
+	box := cString(t, "x")
+	if got := takeString(Place(3, 3, 0.5, 0.5, box)); got != "   \n x \n   " {
+		t.Errorf("Place = %q", got)
+	}
+	if got := takeString(PlaceHorizontal(3, 1, box)); got != "  x" {
+		t.Errorf("PlaceHorizontal = %q", got)
+	}
+	if got := takeString(PlaceVertical(2, 1, box)); got != " \nx" {
+		t.Errorf("PlaceVertical = %q", got)
+	}
+
+	for name, fn := range map[string]func() *cChar{
+		"Place":                    func() *cChar { return Place(-1, 3, 0, 0, box) },
+		"Place position":           func() *cChar { return Place(3, 3, 0, 1.5, box) },
+		"PlaceHorizontal":          func() *cChar { return PlaceHorizontal(-1, 0, box) },
+		"PlaceHorizontal position": func() *cChar { return PlaceHorizontal(3, cDouble(math.NaN()), box) },
+		"PlaceVertical":            func() *cChar { return PlaceVertical(-1, 0, box) },
+		"PlaceVertical position":   func() *cChar { return PlaceVertical(3, -0.5, box) },
+	} {
+		var got string
+		op, _, _ := strings.Cut(name, " ")
+		expectLog(t, op, func() { got = takeString(fn()) })
+		if got != "" {
+			t.Errorf("%s with an invalid size or position = %q, want empty", op, got)
+		}
+	}
+}
+
+func TestStyleRunes(t *testing.T) {
+	matched := lipgloss.NewStyle().Bold(true)
+	unmatched := lipgloss.NewStyle()
+	str := cString(t, "abc")
+
+	got := takeString(StyleRunes(str, cInts(t, []int{1}), 1, unsafe.Pointer(&matched), unsafe.Pointer(&unmatched)))
+	if want := "a" + matched.Render("b") + "c"; got != want {
+		t.Errorf("StyleRunes = %q, want %q", got, want)
+	}
+
+	expectLog(t, "invalid indices", func() {
+		got = takeString(StyleRunes(str, nil, 0, unsafe.Pointer(&matched), unsafe.Pointer(&unmatched)))
+	})
+	if got != "" {
+		t.Errorf("StyleRunes without indices = %q, want empty", got)
+	}
+	expectLog(t, "nil style", func() {
+		got = takeString(StyleRunes(str, cInts(t, []int{0}), 1, nil, unsafe.Pointer(&unmatched)))
+	})
+	if got != "" {
+		t.Errorf("StyleRunes without a matched style = %q, want empty", got)
+	}
+}
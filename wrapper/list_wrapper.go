@@ -4,110 +4,345 @@ package main
 #include <stdlib.h>
 #include <stdint.h>
 #include "lipgloss_types.h"
+
+static inline const char* callListEnumerator(CListEnumeratorFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
 */
 import "C"
 import (
-	"sync"
-	"sync/atomic"
+	"unsafe"
 
-	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/list"
+	"github.com/charmbracelet/lipgloss/tree"
 )
 
-// listRegistry manages list instances with thread safety
-type listRegistry struct {
-	sync.RWMutex
-	nextID uint64
-	lists  map[uint64]*list.List
+// listNode is the wrapper-side model of a list. Like treeNode it is built
+// into a fresh lipgloss tree on every render, which is what lipgloss lists
+// are under the hood. Keeping the model lets us hide single items, apply
+// offsets and track checkbox state, none of which list.List supports.
+type listNode struct {
+	items           []listItem
+	hidden          bool
+	offset          [2]int
+	checked         map[int]bool
+	enumerator      list.Enumerator
+	itemStyle       tree.StyleFunc
+	enumeratorStyle tree.StyleFunc
 }
 
-var listReg = &listRegistry{
-	lists: make(map[uint64]*list.List),
+// listItem is a list entry. Sublists are attached to the item before them,
+// which becomes their label, matching how lipgloss nests lists.
+type listItem struct {
+	value  string
+	hidden bool
+	sub    *listNode
 }
 
-// Register adds a list to the registry and returns its ID
-func (r *listRegistry) Register(l *list.List) uint64 {
-	if l == nil {
-		return 0
+func newListNode() *listNode {
+	return &listNode{
+		checked:    make(map[int]bool),
+		enumerator: list.Bullet,
 	}
-	r.Lock()
-	defer r.Unlock()
+}
 
-	id := atomic.AddUint64(&r.nextID, 1)
-	r.lists[id] = l
-	return id
+// window returns the range of items left after applying the offset
+func (l *listNode) window() (int, int) {
+	start, end := l.offset[0], len(l.items)-l.offset[1]
+	if start > len(l.items) {
+		start = len(l.items)
+	}
+	if end < start {
+		end = start
+	}
+	return start, end
 }
 
-// Get retrieves a list from the registry
-func (r *listRegistry) Get(id uint64) *list.List {
-	r.RLock()
-	defer r.RUnlock()
-	return r.lists[id]
+// build converts the model into a lipgloss tree rendered as a list
+func (l *listNode) build() *tree.Tree {
+	start, end := l.window()
+	enumerator := l.enumerator
+
+	t := tree.New()
+	t.Hide(l.hidden)
+	t.Enumerator(func(children tree.Children, index int) string {
+		return enumerator(children, index)
+	})
+	t.Indenter(func(tree.Children, int) string { return " " })
+	if l.itemStyle != nil {
+		t.ItemStyleFunc(l.itemStyle)
+	}
+	if l.enumeratorStyle != nil {
+		t.EnumeratorStyleFunc(l.enumeratorStyle)
+	}
+
+	for _, item := range l.items[start:end] {
+		// A hidden sublist collapses into its label
+		if item.sub == nil || item.sub.hidden {
+			hidden := item.hidden || item.value == "" && item.sub != nil
+			t.Child(&leafNode{value: item.value, hidden: hidden})
+			continue
+		}
+		sub := item.sub.build().Root(item.value)
+		if item.hidden {
+			sub.Hide(true)
+		}
+		t.Child(sub)
+	}
+	return t
 }
 
-// Remove deletes a list from the registry
-func (r *listRegistry) Remove(id uint64) {
-	r.Lock()
-	defer r.Unlock()
-	delete(r.lists, id)
+// contains reports whether other is l or one of its sublists
+func (l *listNode) contains(other *listNode) bool {
+	if l == other {
+		return true
+	}
+	for _, item := range l.items {
+		if item.sub != nil && item.sub.contains(other) {
+			return true
+		}
+	}
+	return false
 }
 
+// checkbox enumerates items as "[ ]" or "[x]" depending on their checked
+// state. Indexes are shifted back by the offset so that checked state stays
+// attached to the item rather than to its rendered position.
+func (l *listNode) checkbox(_ list.Items, index int) string {
+	start, _ := l.window()
+	if l.checked[start+index] {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+// listRegistry manages list instances with thread safety
+type listRegistry struct {
+	handleRegistry[listNode]
+}
+
+var listReg = &listRegistry{handleRegistry[listNode]{kind: handleKindList}}
+
 //export NewList
 func NewList() C.uint64_t {
-	l := list.New()
-	return C.uint64_t(listReg.Register(l))
+	defer recoverPanic("NewList")
+	return C.uint64_t(listReg.Register(newListNode()))
 }
 
 //export ListAddItem
 func ListAddItem(id C.uint64_t, item *C.char) {
+	defer recoverPanic("ListAddItem")
 	l := listReg.Get(uint64(id))
 	if l == nil {
 		return
 	}
-	l.Item(C.GoString(item))
+	l.items = append(l.items, listItem{value: C.GoString(item)})
+}
+
+//export ListAddSublist
+func ListAddSublist(parentID C.uint64_t, childID C.uint64_t) {
+	defer recoverPanic("ListAddSublist")
+	parent := listReg.Get(uint64(parentID))
+	child := listReg.Get(uint64(childID))
+	if parent == nil || child == nil {
+		return
+	}
+	if child.contains(parent) {
+		Log(LogLevelError, "ListAddSublist: list %d already contains list %d", uint64(childID), uint64(parentID))
+		return
+	}
+	if n := len(parent.items); n > 0 && parent.items[n-1].sub == nil {
+		parent.items[n-1].sub = child
+		return
+	}
+	parent.items = append(parent.items, listItem{sub: child})
+}
+
+//export ListItemCount
+func ListItemCount(id C.uint64_t) C.int {
+	defer recoverPanic("ListItemCount")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return 0
+	}
+	return C.int(len(l.items))
+}
+
+//export ListSetItemChecked
+func ListSetItemChecked(id C.uint64_t, index C.int, checked C.int) C.int {
+	defer recoverPanic("ListSetItemChecked")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return 0
+	}
+	if index < 0 || int(index) >= len(l.items) {
+		Log(LogLevelError, "ListSetItemChecked: index %d out of range for list %d", int(index), uint64(id))
+		return 0
+	}
+	l.checked[int(index)] = String.ToBool(checked)
+	return 1
+}
+
+//export ListSetItemHidden
+func ListSetItemHidden(id C.uint64_t, index C.int, hide C.int) C.int {
+	defer recoverPanic("ListSetItemHidden")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return 0
+	}
+	if index < 0 || int(index) >= len(l.items) {
+		Log(LogLevelError, "ListSetItemHidden: index %d out of range for list %d", int(index), uint64(id))
+		return 0
+	}
+	l.items[index].hidden = String.ToBool(hide)
+	return 1
+}
+
+//export ListSetHidden
+func ListSetHidden(id C.uint64_t, hide C.int) {
+	defer recoverPanic("ListSetHidden")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	l.hidden = String.ToBool(hide)
+}
+
+// ListSetOffset skips start items from the beginning and end items from the
+// end of the list when rendering.
+//
+//export ListSetOffset
+func ListSetOffset(id C.uint64_t, start, end C.int) {
+	defer recoverPanic("ListSetOffset")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	if err := Validate.Dimension(int(start), "offset start"); err != nil {
+		Log(LogLevelError, "ListSetOffset validation error: %v", err)
+		return
+	}
+	if err := Validate.Dimension(int(end), "offset end"); err != nil {
+		Log(LogLevelError, "ListSetOffset validation error: %v", err)
+		return
+	}
+	l.offset = [2]int{int(start), int(end)}
 }
 
 //export ListSetEnumerator
 func ListSetEnumerator(id C.uint64_t, enumeratorType C.int) {
+	defer recoverPanic("ListSetEnumerator")
 	l := listReg.Get(uint64(id))
 	if l == nil {
 		return
 	}
 	switch enumeratorType {
-	case 0:
-		l.Enumerator(list.Bullet)
-	case 1:
-		l.Enumerator(list.Dash)
-	case 2:
-		l.Enumerator(list.Alphabet)
-	case 3:
-		l.Enumerator(list.Arabic)
-	case 4:
-		l.Enumerator(list.Roman)
+	case C.LIST_ENUM_BULLET:
+		l.enumerator = list.Bullet
+	case C.LIST_ENUM_DASH:
+		l.enumerator = list.Dash
+	case C.LIST_ENUM_ALPHABET:
+		l.enumerator = list.Alphabet
+	case C.LIST_ENUM_ARABIC:
+		l.enumerator = list.Arabic
+	case C.LIST_ENUM_ROMAN:
+		l.enumerator = list.Roman
+	case C.LIST_ENUM_ASTERISK:
+		l.enumerator = list.Asterisk
+	case C.LIST_ENUM_CHECKBOX:
+		l.enumerator = l.checkbox
+	default:
+		Log(LogLevelError, "ListSetEnumerator: unknown enumerator type %d", int(enumeratorType))
+	}
+}
+
+//export ListSetEnumeratorFunc
+func ListSetEnumeratorFunc(id C.uint64_t, fn C.CListEnumeratorFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("ListSetEnumeratorFunc")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "ListSetEnumeratorFunc received nil callback")
+		return
+	}
+	l.enumerator = func(items list.Items, index int) string {
+		return C.GoString(C.callListEnumerator(fn, C.int(items.Length()), C.int(index), userdata))
 	}
 }
 
 //export ListSetItemStyle
-func ListSetItemStyle(id C.uint64_t, style *C.char) {
+func ListSetItemStyle(id C.uint64_t, styleID C.uint64_t) {
+	defer recoverPanic("ListSetItemStyle")
 	l := listReg.Get(uint64(id))
 	if l == nil {
 		return
 	}
-	styled := lipgloss.NewStyle().Foreground(lipgloss.Color(C.GoString(style)))
-	l.ItemStyle(styled)
+	style, err := Style.SafeGet(uint64(styleID), "list-item-style")
+	if err != nil {
+		Log(LogLevelError, "ListSetItemStyle style error: %v", err)
+		return
+	}
+	l.itemStyle = staticTreeStyle(*style)
+}
+
+//export ListSetItemStyleFunc
+func ListSetItemStyleFunc(id C.uint64_t, fn C.CStyleFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("ListSetItemStyleFunc")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "ListSetItemStyleFunc received nil callback")
+		return
+	}
+	l.itemStyle = callbackTreeStyle(fn, userdata, "list-item-style-func")
+}
+
+//export ListSetEnumeratorStyle
+func ListSetEnumeratorStyle(id C.uint64_t, styleID C.uint64_t) {
+	defer recoverPanic("ListSetEnumeratorStyle")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	style, err := Style.SafeGet(uint64(styleID), "list-enumerator-style")
+	if err != nil {
+		Log(LogLevelError, "ListSetEnumeratorStyle style error: %v", err)
+		return
+	}
+	l.enumeratorStyle = staticTreeStyle(*style)
+}
+
+//export ListSetEnumeratorStyleFunc
+func ListSetEnumeratorStyleFunc(id C.uint64_t, fn C.CStyleFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("ListSetEnumeratorStyleFunc")
+	l := listReg.Get(uint64(id))
+	if l == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "ListSetEnumeratorStyleFunc received nil callback")
+		return
+	}
+	l.enumeratorStyle = callbackTreeStyle(fn, userdata, "list-enumerator-style-func")
 }
 
 //export RenderList
-func RenderList(id C.uint64_t) *C.char {
+func RenderList(id C.uint64_t) (ret *C.char) {
+	defer recoverString("RenderList", &ret, "")
 	l := listReg.Get(uint64(id))
 	if l == nil {
-		return C.CString("")
+		return Memory.CString("", "RenderList result")
 	}
-	result := l.String()
-	return C.CString(result)
+	result := l.build().String()
+	return Memory.CString(result, "RenderList result")
 }
 
 //export FreeList
 func FreeList(id C.uint64_t) {
+	defer recoverPanic("FreeList")
 	listReg.Remove(uint64(id))
 }
@@ -0,0 +1,171 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestList returns a list of items freed when the test ends
+func newTestList(t testing.TB, items ...string) cHandle {
+	id := NewList()
+	t.Cleanup(func() { FreeList(id) })
+	for _, item := range items {
+		ListAddItem(id, cString(t, item))
+	}
+	return id
+}
+
+// renderList renders a list and frees the result
+func renderList(id cHandle) string {
+	return takeString(RenderList(id))
+}
+
+func TestRenderList(t *testing.T) {
+	if got := renderList(newTestList(t)); got != "" {
+		t.Errorf("an empty list renders %q", got)
+	}
+
+	groceries := newTestList(t, "Bread", "Fruit")
+	fruit := newTestList(t, "Apple", "Pear")
+	ListAddSublist(groceries, fruit)
+	ListAddItem(groceries, cString(t, "Milk"))
+	if got := ListItemCount(groceries); got != 3 {
+		t.Errorf("ListItemCount = %d, want 3 (sublists attach to the item before them)", got)
+	}
+	golden(t, "list_bullet", renderList(groceries))
+
+	for _, c := range []struct {
+		enum cInt
+		want string
+	}{
+		{listEnumDash, "- Bread"},
+		{listEnumAlphabet, "C. Milk"},
+		{listEnumArabic, "3. Milk"},
+		{listEnumRoman, "III. Milk"},
+		{listEnumAsterisk, "* Bread"},
+	} {
+		ListSetEnumerator(groceries, c.enum)
+		if got := renderList(groceries); !strings.Contains(got, c.want) {
+			t.Errorf("with enumerator %d the list renders\n%s\nwant a line %q", c.enum, got, c.want)
+		}
+	}
+
+	// A sublist without a preceding item gets an empty label
+	orphan := newTestList(t)
+	ListAddSublist(orphan, newTestList(t, "x"))
+	if got := ListItemCount(orphan); got != 1 {
+		t.Errorf("ListItemCount with a leading sublist = %d, want 1", got)
+	}
+
+	expectLog(t, "unknown enumerator type 9", func() { ListSetEnumerator(groceries, 9) })
+	expectLog(t, "already contains", func() { ListAddSublist(fruit, groceries) })
+	expectLog(t, "already contains", func() { ListAddSublist(fruit, fruit) })
+}
+
+func TestListItems(t *testing.T) {
+	todo := newTestList(t, "a", "b", "c", "d")
+	ListSetEnumerator(todo, listEnumCheckbox)
+	if ListSetItemChecked(todo, 1, 1) != 1 {
+		t.Fatal("ListSetItemChecked(1) failed")
+	}
+	if got, want := renderList(todo), "[ ] a\n[x] b\n[ ] c\n[ ] d"; got != want {
+		t.Errorf("the checklist renders %q, want %q", got, want)
+	}
+
+	// Checked state follows the item when an offset shifts it
+	ListSetOffset(todo, 1, 1)
+	if got, want := renderList(todo), "[x] b\n[ ] c"; got != want {
+		t.Errorf("with an offset the checklist renders %q, want %q", got, want)
+	}
+	ListSetOffset(todo, 3, 3)
+	if got := renderList(todo); got != "" {
+		t.Errorf("with an offset past the end the list renders %q", got)
+	}
+	ListSetOffset(todo, 0, 0)
+
+	if ListSetItemHidden(todo, 0, 1) != 1 {
+		t.Fatal("ListSetItemHidden(0) failed")
+	}
+	if got, want := renderList(todo), "[x] b\n[ ] c\n[ ] d"; got != want {
+		t.Errorf("with a hidden item the list renders %q, want %q", got, want)
+	}
+	ListSetHidden(todo, 1)
+	if got := renderList(todo); got != "" {
+		t.Errorf("a hidden list renders %q", got)
+	}
+
+	expectLog(t, "ListSetItemChecked: index 4", func() {
+		if got := ListSetItemChecked(todo, 4, 1); got != 0 {
+			t.Errorf("ListSetItemChecked out of range = %d, want 0", got)
+		}
+	})
+	expectLog(t, "ListSetItemHidden: index -1", func() {
+		if got := ListSetItemHidden(todo, -1, 1); got != 0 {
+			t.Errorf("ListSetItemHidden out of range = %d, want 0", got)
+		}
+	})
+	expectLog(t, "ListSetOffset validation error", func() { ListSetOffset(todo, -1, 0) })
+	expectLog(t, "ListSetOffset validation error", func() { ListSetOffset(todo, 0, -1) })
+}
+
+func TestListStyles(t *testing.T) {
+	id := newTestList(t, "a", "b", "c")
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	red := keep(t, StyleForeground(keep(t, NewStyle()), cString(t, "#ff0000")))
+
+	ListSetItemStyle(id, red)
+	ListSetEnumeratorStyle(id, bold)
+	golden(t, "list_styled", renderList(id))
+
+	ListSetItemStyleFunc(id, evenItemStyleFunc(), cHandlePtr(t, red))
+	ListSetEnumeratorStyleFunc(id, evenItemStyleFunc(), cHandlePtr(t, bold))
+	ListSetEnumeratorFunc(id, arrowListEnumerator(), nil)
+	golden(t, "list_callbacks", renderList(id))
+
+	expectLog(t, "ListSetItemStyle style error", func() { ListSetItemStyle(id, stale()) })
+	expectLog(t, "ListSetEnumeratorStyle style error", func() { ListSetEnumeratorStyle(id, stale()) })
+	for name, fn := range map[string]func(){
+		"ListSetEnumeratorFunc":      func() { ListSetEnumeratorFunc(id, nil, nil) },
+		"ListSetItemStyleFunc":       func() { ListSetItemStyleFunc(id, nil, nil) },
+		"ListSetEnumeratorStyleFunc": func() { ListSetEnumeratorStyleFunc(id, nil, nil) },
+	} {
+		expectLog(t, name+" received nil callback", fn)
+	}
+}
+
+func TestListErrors(t *testing.T) {
+	id := NewList()
+	FreeList(id)
+	live := newTestList(t, "live")
+	style := keep(t, NewStyle())
+
+	for name, fn := range map[string]func(){
+		"ListAddItem":                func() { ListAddItem(id, cString(t, "x")) },
+		"ListAddSublist":             func() { ListAddSublist(id, live) },
+		"ListAddSublist child":       func() { ListAddSublist(live, id) },
+		"ListItemCount":              func() { ListItemCount(id) },
+		"ListSetItemChecked":         func() { ListSetItemChecked(id, 0, 1) },
+		"ListSetItemHidden":          func() { ListSetItemHidden(id, 0, 1) },
+		"ListSetHidden":              func() { ListSetHidden(id, 1) },
+		"ListSetOffset":              func() { ListSetOffset(id, 0, 0) },
+		"ListSetEnumerator":          func() { ListSetEnumerator(id, 0) },
+		"ListSetEnumeratorFunc":      func() { ListSetEnumeratorFunc(id, arrowListEnumerator(), nil) },
+		"ListSetItemStyle":           func() { ListSetItemStyle(id, style) },
+		"ListSetItemStyleFunc":       func() { ListSetItemStyleFunc(id, evenItemStyleFunc(), nil) },
+		"ListSetEnumeratorStyle":     func() { ListSetEnumeratorStyle(id, style) },
+		"ListSetEnumeratorStyleFunc": func() { ListSetEnumeratorStyleFunc(id, evenItemStyleFunc(), nil) },
+		"FreeList":                   func() { FreeList(id) },
+	} {
+		if got := logged(fn); !strings.Contains(got, "list handle") {
+			t.Errorf("%s on a freed list logged %q", name, got)
+		}
+	}
+	if got := ListItemCount(id); got != 0 {
+		t.Errorf("ListItemCount of a freed list = %d, want 0", got)
+	}
+	if got := renderList(id); got != "" {
+		t.Errorf("RenderList of a freed list = %q", got)
+	}
+}
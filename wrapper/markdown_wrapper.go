@@ -0,0 +1,655 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/list"
+	"github.com/charmbracelet/lipgloss/table"
+)
+
+// Markdown element names looked up in the theme passed to RenderMarkdown.
+// Headings fall back from "h1".."h6" to "heading"; every other element falls
+// back to the built-in default style.
+const (
+	mdHeading        = "heading"
+	mdParagraph      = "paragraph"
+	mdStrong         = "strong"
+	mdEmphasis       = "emphasis"
+	mdStrikethrough  = "strikethrough"
+	mdCode           = "code"
+	mdCodeBlock      = "code_block"
+	mdLink           = "link"
+	mdBlockquote     = "blockquote"
+	mdRule           = "hr"
+	mdListItem       = "list_item"
+	mdListEnumerator = "list_enumerator"
+	mdTableHeader    = "table_header"
+	mdTableCell      = "table_cell"
+	mdTableBorder    = "table_border"
+)
+
+// markdownRuleWidth is the width of rendered thematic breaks
+const markdownRuleWidth = 40
+
+var defaultMarkdownStyles = map[string]lipgloss.Style{
+	"h1":             lipgloss.NewStyle().Bold(true).Underline(true).Foreground(lipgloss.Color("13")),
+	"h2":             lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12")),
+	mdHeading:        lipgloss.NewStyle().Bold(true),
+	mdParagraph:      lipgloss.NewStyle(),
+	mdStrong:         lipgloss.NewStyle().Bold(true),
+	mdEmphasis:       lipgloss.NewStyle().Italic(true),
+	mdStrikethrough:  lipgloss.NewStyle().Strikethrough(true),
+	mdCode:           lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+	mdCodeBlock:      lipgloss.NewStyle().Foreground(lipgloss.Color("245")).PaddingLeft(2),
+	mdLink:           lipgloss.NewStyle().Underline(true).Foreground(lipgloss.Color("6")),
+	mdBlockquote:     lipgloss.NewStyle().Italic(true).PaddingLeft(1).Border(lipgloss.NormalBorder(), false, false, false, true),
+	mdRule:           lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+	mdListItem:       lipgloss.NewStyle(),
+	mdListEnumerator: lipgloss.NewStyle(),
+	mdTableHeader:    lipgloss.NewStyle().Bold(true).Padding(0, 1),
+	mdTableCell:      lipgloss.NewStyle().Padding(0, 1),
+	mdTableBorder:    lipgloss.NewStyle(),
+}
+
+type mdBlockKind int
+
+const (
+	mdBlockParagraph mdBlockKind = iota
+	mdBlockHeading
+	mdBlockCode
+	mdBlockQuote
+	mdBlockRule
+	mdBlockList
+	mdBlockTable
+)
+
+// mdBlock is a parsed block-level Markdown element
+type mdBlock struct {
+	kind     mdBlockKind
+	level    int
+	text     string
+	lines    []string
+	children []mdBlock
+	items    []mdItem
+	ordered  bool
+	start    int
+	header   []string
+	rows     [][]string
+	align    []lipgloss.Position
+}
+
+// mdItem is a list item and the blocks nested below it
+type mdItem struct {
+	blocks []mdBlock
+}
+
+var (
+	mdHeadingRe   = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	mdRuleRe      = regexp.MustCompile(`^ {0,3}((\*[ \t]*){3,}|(-[ \t]*){3,}|(_[ \t]*){3,})$`)
+	mdFenceRe     = regexp.MustCompile("^( {0,3})(`{3,}|~{3,})")
+	mdListItemRe  = regexp.MustCompile(`^( *)([-*+]|(\d{1,9})[.)])( +|$)(.*)$`)
+	mdQuoteRe     = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	mdTableSepRe  = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	mdLinkInlineR = regexp.MustCompile(`^\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+)
+
+// markdownRenderer renders parsed Markdown with styles from a theme
+type markdownRenderer struct {
+	theme *theme
+}
+
+// style resolves the style for an element, trying fallbacks in order
+func (r *markdownRenderer) style(names ...string) lipgloss.Style {
+	if r.theme != nil {
+		for _, name := range names {
+			if s, ok := r.theme.Style(name); ok {
+				return s
+			}
+		}
+	}
+	for _, name := range names {
+		if s, ok := defaultMarkdownStyles[name]; ok {
+			return s
+		}
+	}
+	return lipgloss.NewStyle()
+}
+
+func expandTabs(line string) string {
+	return strings.ReplaceAll(line, "\t", "    ")
+}
+
+func isBlank(line string) bool {
+	return strings.TrimSpace(line) == ""
+}
+
+func indentOf(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+// splitTableRow splits a pipe table row into trimmed cells
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+
+	var cells []string
+	var cell strings.Builder
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteByte('|')
+			i++
+		case line[i] == '|':
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(line[i])
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
+
+// startsBlock reports whether line interrupts a paragraph
+func startsBlock(line string) bool {
+	return mdHeadingRe.MatchString(line) || mdRuleRe.MatchString(line) ||
+		mdFenceRe.MatchString(line) || mdQuoteRe.MatchString(line) ||
+		mdListItemRe.MatchString(line)
+}
+
+// parseMarkdown splits Markdown source into blocks
+func parseMarkdown(lines []string) []mdBlock {
+	var blocks []mdBlock
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		if isBlank(line) {
+			i++
+			continue
+		}
+
+		if m := mdFenceRe.FindStringSubmatch(line); m != nil {
+			fence := m[2]
+			var code []string
+			for i++; i < len(lines); i++ {
+				trimmed := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+					i++
+					break
+				}
+				code = append(code, strings.TrimPrefix(lines[i], m[1]))
+			}
+			blocks = append(blocks, mdBlock{kind: mdBlockCode, lines: code})
+			continue
+		}
+
+		if m := mdHeadingRe.FindStringSubmatch(line); m != nil {
+			blocks = append(blocks, mdBlock{kind: mdBlockHeading, level: len(m[1]), text: m[2]})
+			i++
+			continue
+		}
+
+		if mdRuleRe.MatchString(line) {
+			blocks = append(blocks, mdBlock{kind: mdBlockRule})
+			i++
+			continue
+		}
+
+		if mdQuoteRe.MatchString(line) {
+			var quoted []string
+			for ; i < len(lines); i++ {
+				m := mdQuoteRe.FindStringSubmatch(lines[i])
+				if m == nil {
+					// Lazy continuation of a quoted paragraph
+					if isBlank(lines[i]) || startsBlock(lines[i]) || len(quoted) == 0 || isBlank(quoted[len(quoted)-1]) {
+						break
+					}
+					quoted = append(quoted, lines[i])
+					continue
+				}
+				quoted = append(quoted, m[1])
+			}
+			blocks = append(blocks, mdBlock{kind: mdBlockQuote, children: parseMarkdown(quoted)})
+			continue
+		}
+
+		if mdListItemRe.MatchString(line) {
+			block, next := parseList(lines, i)
+			blocks = append(blocks, block)
+			i = next
+			continue
+		}
+
+		if strings.Contains(line, "|") && i+1 < len(lines) && mdTableSepRe.MatchString(lines[i+1]) &&
+			strings.Contains(lines[i+1], "-") {
+			block, next := parseTable(lines, i)
+			blocks = append(blocks, block)
+			i = next
+			continue
+		}
+
+		var para []string
+		for ; i < len(lines); i++ {
+			if isBlank(lines[i]) || (len(para) > 0 && startsBlock(lines[i])) {
+				break
+			}
+			para = append(para, lines[i])
+		}
+		blocks = append(blocks, mdBlock{kind: mdBlockParagraph, text: joinParagraph(para)})
+	}
+	return blocks
+}
+
+// joinParagraph joins paragraph lines, keeping hard line breaks
+func joinParagraph(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		hard := strings.HasSuffix(line, "  ") || strings.HasSuffix(line, `\`)
+		line = strings.TrimSpace(line)
+		if hard {
+			line = strings.TrimSuffix(line, `\`)
+		}
+		b.WriteString(line)
+		if i < len(lines)-1 {
+			if hard {
+				b.WriteString("\n")
+			} else {
+				b.WriteString(" ")
+			}
+		}
+	}
+	return b.String()
+}
+
+// parseList parses a list starting at lines[i] and returns it along with
+// the index of the first line after it
+func parseList(lines []string, i int) (mdBlock, int) {
+	first := mdListItemRe.FindStringSubmatch(lines[i])
+	base := len(first[1])
+	ordered := first[3] != ""
+	block := mdBlock{kind: mdBlockList, ordered: ordered, start: 1}
+	if ordered {
+		fmt.Sscanf(first[3], "%d", &block.start)
+	}
+
+	var content []string
+	contentIndent := 0
+	flush := func() {
+		if content != nil {
+			block.items = append(block.items, mdItem{blocks: parseMarkdown(content)})
+		}
+		content = nil
+	}
+
+	for i < len(lines) {
+		line := lines[i]
+		m := mdListItemRe.FindStringSubmatch(line)
+
+		if m != nil && len(m[1]) >= base && len(m[1]) < base+2 {
+			if (m[3] != "") != ordered {
+				break
+			}
+			flush()
+			contentIndent = len(m[1]) + len(m[2]) + len(m[4])
+			if m[4] == "" || len(m[4]) > 4 {
+				contentIndent = len(m[1]) + len(m[2]) + 1
+			}
+			content = []string{m[5]}
+			i++
+			continue
+		}
+
+		if isBlank(line) {
+			// A blank line only continues the list if more of it follows
+			j := i + 1
+			for j < len(lines) && isBlank(lines[j]) {
+				j++
+			}
+			if j >= len(lines) || indentOf(lines[j]) < contentIndent && !isListItemAt(lines[j], base) {
+				break
+			}
+			content = append(content, "")
+			i++
+			continue
+		}
+
+		if indentOf(line) >= contentIndent {
+			content = append(content, line[contentIndent:])
+			i++
+			continue
+		}
+
+		// Lazy paragraph continuation
+		if len(content) > 0 && !isBlank(content[len(content)-1]) && !startsBlock(line) {
+			content = append(content, strings.TrimSpace(line))
+			i++
+			continue
+		}
+		break
+	}
+	flush()
+	return block, i
+}
+
+func isListItemAt(line string, base int) bool {
+	m := mdListItemRe.FindStringSubmatch(line)
+	return m != nil && len(m[1]) >= base && len(m[1]) < base+2
+}
+
+// parseTable parses a pipe table whose header is lines[i]
+func parseTable(lines []string, i int) (mdBlock, int) {
+	header := splitTableRow(lines[i])
+	block := mdBlock{kind: mdBlockTable, header: header}
+
+	for _, spec := range splitTableRow(lines[i+1]) {
+		switch {
+		case strings.HasPrefix(spec, ":") && strings.HasSuffix(spec, ":"):
+			block.align = append(block.align, lipgloss.Center)
+		case strings.HasSuffix(spec, ":"):
+			block.align = append(block.align, lipgloss.Right)
+		default:
+			block.align = append(block.align, lipgloss.Left)
+		}
+	}
+
+	for i += 2; i < len(lines) && !isBlank(lines[i]) && strings.Contains(lines[i], "|"); i++ {
+		row := splitTableRow(lines[i])
+		cells := make([]string, len(header))
+		copy(cells, row)
+		block.rows = append(block.rows, cells)
+	}
+	return block, i
+}
+
+// inline renders emphasis, code spans and links within text
+func (r *markdownRenderer) inline(text string) string {
+	var out strings.Builder
+	var plain strings.Builder
+	flushPlain := func() {
+		out.WriteString(plain.String())
+		plain.Reset()
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && unicode.IsPunct(rune(text[i+1])) || c == '\\' && i+1 < len(text) && unicode.IsSymbol(rune(text[i+1])):
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			n := 1
+			for i+n < len(text) && text[i+n] == '`' {
+				n++
+			}
+			delim := text[i : i+n]
+			if end := strings.Index(text[i+n:], delim); end >= 0 {
+				code := strings.TrimSpace(text[i+n : i+n+end])
+				flushPlain()
+				out.WriteString(r.style(mdCode).Render(code))
+				i += 2*n + end
+				continue
+			}
+			plain.WriteString(delim)
+			i += n
+			continue
+
+		case c == '[':
+			if m := mdLinkInlineR.FindStringSubmatch(text[i:]); m != nil {
+				flushPlain()
+				label := m[1]
+				if label == "" {
+					label = m[2]
+				}
+				out.WriteString(r.style(mdLink).Render(stripInline(label)))
+				if m[2] != "" && m[2] != label {
+					out.WriteString(" (" + m[2] + ")")
+				}
+				i += len(m[0])
+				continue
+			}
+
+		case c == '~' && strings.HasPrefix(text[i:], "~~"):
+			if end := strings.Index(text[i+2:], "~~"); end > 0 {
+				flushPlain()
+				out.WriteString(r.style(mdStrikethrough).Render(stripInline(r.inline(text[i+2 : i+2+end]))))
+				i += end + 4
+				continue
+			}
+
+		case c == '*' || c == '_':
+			delim := string(c)
+			name := mdEmphasis
+			if i+1 < len(text) && text[i+1] == c {
+				delim += string(c)
+				name = mdStrong
+			}
+			if end := findClosingDelimiter(text, i, delim); end > 0 {
+				flushPlain()
+				inner := text[i+len(delim) : end]
+				out.WriteString(r.style(name).Render(stripInline(r.inline(inner))))
+				i = end + len(delim)
+				continue
+			}
+			plain.WriteString(delim)
+			i += len(delim)
+			continue
+		}
+
+		_, size := utf8.DecodeRuneInString(text[i:])
+		plain.WriteString(text[i : i+size])
+		i += size
+	}
+	flushPlain()
+	return out.String()
+}
+
+// stripInline removes escape sequences from nested spans before they are
+// restyled, so the outer style is not cut short by inner resets
+func stripInline(s string) string {
+	return ansiEscapeRe.ReplaceAllString(s, "")
+}
+
+var ansiEscapeRe = regexp.MustCompile("\x1b\\[[0-9;:]*[A-Za-z]")
+
+// findClosingDelimiter returns the index of the delimiter closing the one
+// opened at text[open], or -1
+func findClosingDelimiter(text string, open int, delim string) int {
+	start := open + len(delim)
+	if start >= len(text) || text[start] == ' ' {
+		return -1
+	}
+	// Intraword underscores do not open emphasis
+	if delim[0] == '_' && open > 0 && isWordByte(text[open-1]) {
+		return -1
+	}
+	for j := start; j+len(delim) <= len(text); j++ {
+		if text[j] == '`' {
+			if end := strings.IndexByte(text[j+1:], '`'); end >= 0 {
+				j += end + 1
+				continue
+			}
+		}
+		// A single delimiter never closes inside a longer run, which
+		// belongs to a nested span
+		if len(delim) == 1 && text[j] == delim[0] {
+			run := j
+			for run < len(text) && text[run] == delim[0] {
+				run++
+			}
+			if run-j > 1 {
+				j = run - 1
+				continue
+			}
+		}
+		if !strings.HasPrefix(text[j:], delim) || text[j-1] == ' ' {
+			continue
+		}
+		after := j + len(delim)
+		if delim[0] == '_' && after < len(text) && isWordByte(text[after]) {
+			continue
+		}
+		if j > start {
+			return j
+		}
+	}
+	return -1
+}
+
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= 0x80
+}
+
+// renderBlocks renders a sequence of blocks separated by blank lines
+func (r *markdownRenderer) renderBlocks(blocks []mdBlock) string {
+	rendered := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		rendered = append(rendered, r.renderBlock(b))
+	}
+	return strings.Join(rendered, "\n\n")
+}
+
+func (r *markdownRenderer) renderBlock(b mdBlock) string {
+	switch b.kind {
+	case mdBlockHeading:
+		return r.style(fmt.Sprintf("h%d", b.level), mdHeading).Render(r.inline(b.text))
+	case mdBlockCode:
+		return r.style(mdCodeBlock).Render(strings.Join(b.lines, "\n"))
+	case mdBlockQuote:
+		return r.style(mdBlockquote).Render(r.renderBlocks(b.children))
+	case mdBlockRule:
+		return r.style(mdRule).Render(strings.Repeat("─", markdownRuleWidth))
+	case mdBlockList:
+		return r.buildList(b).build().String()
+	case mdBlockTable:
+		return r.renderTable(b)
+	default:
+		return r.style(mdParagraph).Render(r.inline(b.text))
+	}
+}
+
+// buildList converts a parsed list into the list model used by NewList, so
+// Markdown lists render exactly like lists built through the C API
+func (r *markdownRenderer) buildList(b mdBlock) *listNode {
+	l := newListNode()
+	if b.ordered {
+		start := b.start
+		l.enumerator = func(_ list.Items, i int) string {
+			return fmt.Sprintf("%d.", start+i)
+		}
+	}
+	// Pad enumerators like the default list style does, unless the theme
+	// pads them itself, so nested items line up as in lists built from C
+	enumeratorStyle := r.style(mdListEnumerator)
+	if enumeratorStyle.GetPaddingRight() == 0 {
+		enumeratorStyle = enumeratorStyle.PaddingRight(1)
+	}
+	l.itemStyle = staticTreeStyle(r.style(mdListItem))
+	l.enumeratorStyle = staticTreeStyle(enumeratorStyle)
+
+	for _, item := range b.items {
+		var text []string
+		var sub *listNode
+		for _, child := range item.blocks {
+			if child.kind == mdBlockList && sub == nil {
+				sub = r.buildList(child)
+				continue
+			}
+			if child.kind == mdBlockParagraph {
+				text = append(text, r.inline(child.text))
+				continue
+			}
+			text = append(text, r.renderBlock(child))
+		}
+		l.items = append(l.items, listItem{value: strings.Join(text, "\n"), sub: sub})
+	}
+	return l
+}
+
+func (r *markdownRenderer) renderTable(b mdBlock) string {
+	header := make([]string, len(b.header))
+	for i, cell := range b.header {
+		header[i] = r.inline(cell)
+	}
+	rows := make([][]string, len(b.rows))
+	for i, row := range b.rows {
+		rows[i] = make([]string, len(row))
+		for j, cell := range row {
+			rows[i][j] = r.inline(cell)
+		}
+	}
+
+	headerStyle := r.style(mdTableHeader)
+	cellStyle := r.style(mdTableCell)
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(r.style(mdTableBorder)).
+		Headers(header...).
+		Rows(rows...).
+		StyleFunc(func(row, col int) lipgloss.Style {
+			style := cellStyle
+			if row == table.HeaderRow {
+				style = headerStyle
+			}
+			if col < len(b.align) {
+				style = style.Align(b.align[col])
+			}
+			return style
+		})
+	return t.Render()
+}
+
+// renderMarkdown renders Markdown source with the given theme, which may be
+// nil to use the built-in styles
+func renderMarkdown(source string, t *theme) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+	lines := strings.Split(source, "\n")
+	for i, line := range lines {
+		lines[i] = expandTabs(line)
+	}
+	r := &markdownRenderer{theme: t}
+	return r.renderBlocks(parseMarkdown(lines))
+}
+
+// RenderMarkdown renders Markdown with the styles of a theme. Pass 0 as
+// themeID to use the built-in styles.
+//
+//export RenderMarkdown
+func RenderMarkdown(markdown *C.char, themeID C.uint64_t) (ret *C.char) {
+	defer recoverString("RenderMarkdown", &ret, "")
+	var t *theme
+	if themeID != 0 {
+		t = themeReg.Get(uint64(themeID))
+		if t == nil {
+			Log(LogLevelError, "RenderMarkdown: theme not found with ID: %d", uint64(themeID))
+			cs := Memory.CString("", "RenderMarkdown result")
+			return cs
+		}
+	}
+
+	result := renderMarkdown(String.GoString(markdown), t)
+	cs, err := String.CString(result)
+	if err != nil {
+		Log(LogLevelError, "RenderMarkdown memory allocation error: %v", err)
+		cs = Memory.CString("", "RenderMarkdown result")
+		return cs
+	}
+
+	Memory.Track(unsafe.Pointer(cs), "RenderMarkdown result")
+	return cs
+}
@@ -0,0 +1,148 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const markdownSample = "# Release notes\n\nSome **bold** and `code` text.\n\n- one\n- two\n\n> quoted\n\n```\nfenced\n```\n"
+
+// md renders text with a built-in Markdown element style
+func md(name, text string) string {
+	return defaultMarkdownStyles[name].Render(text)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	golden(t, "markdown_default", takeString(RenderMarkdown(cString(t, markdownSample), 0)))
+
+	theme := LoadTheme(cString(t, `{"styles": {"h1": {"foreground": "#ff5f87", "bold": true}}}`), 1, nil)
+	if theme == 0 {
+		t.Fatal("LoadTheme failed")
+	}
+	t.Cleanup(func() { FreeTheme(theme) })
+	golden(t, "markdown_theme", takeString(RenderMarkdown(cString(t, markdownSample), theme)))
+
+	if got := takeString(RenderMarkdown(nil, 0)); got != "" {
+		t.Errorf("RenderMarkdown(NULL) = %q", got)
+	}
+	expectLog(t, "theme not found", func() {
+		if got := takeString(RenderMarkdown(cString(t, markdownSample), stale())); got != "" {
+			t.Errorf("RenderMarkdown with a freed theme = %q", got)
+		}
+	})
+}
+
+func TestMarkdownInline(t *testing.T) {
+	r := &markdownRenderer{}
+	for _, c := range []struct {
+		name, text, want string
+	}{
+		{"strong", "**bold** text", md(mdStrong, "bold") + " text"},
+		{"emphasis", "an *em* and _em_", "an " + md(mdEmphasis, "em") + " and " + md(mdEmphasis, "em")},
+		{"strikethrough", "~~gone~~", md(mdStrikethrough, "gone")},
+		// Nested spans are restyled by the outer style as a whole
+		{"nested emphasis", "**bold _and italic_**", md(mdStrong, "bold and italic")},
+		{"nested strong", "*a **b** c*", md(mdEmphasis, "a b c")},
+		{"code span", "`a * b`", md(mdCode, "a * b")},
+		{"code span with backticks", "``tick ` inside``", md(mdCode, "tick ` inside")},
+		{"delimiter inside code", "**a `**` b**", md(mdStrong, "a ** b")},
+		{"link", "[site](http://x.y)", md(mdLink, "site") + " (http://x.y)"},
+		{"bare link", "[](http://x.y)", md(mdLink, "http://x.y")},
+		{"escapes", `\*literal\* \[x\]`, "*literal* [x]"},
+		{"intraword underscores", "snake_case_name", "snake_case_name"},
+		{"spaced asterisks", "2 * 3 * 4", "2 * 3 * 4"},
+		{"unclosed strong", "**open", "**open"},
+		{"unclosed code", "`open", "`open"},
+	} {
+		if got := r.inline(c.text); got != c.want {
+			t.Errorf("%s: inline(%q) = %q, want %q", c.name, c.text, got, c.want)
+		}
+	}
+}
+
+func TestMarkdownLists(t *testing.T) {
+	for _, c := range []struct {
+		name, source, want string
+	}{
+		{"bullets", "- one\n* two\n+ three", "• one\n• two\n• three"},
+		{"ordered start", "3. three\n4. four", "3. three\n4. four"},
+		{"nested", "- fruit\n  - apple\n  - pear\n- milk", "• fruit\n  • apple\n  • pear\n• milk"},
+		{"nested ordered", "1. a\n   - b\n2. c", "1. a\n  • b\n2. c"},
+		{"lazy continuation", "- one\ncontinued", "• one continued"},
+		{"loose", "- a\n\n- b", "• a\n• b"},
+	} {
+		if got := renderMarkdown(c.source, nil); got != c.want {
+			t.Errorf("%s: rendered %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	// A different list type starts a new list
+	blocks := parseMarkdown(strings.Split("- a\n1. b", "\n"))
+	if len(blocks) != 2 || blocks[0].ordered || !blocks[1].ordered {
+		t.Errorf("a bullet then an ordered item parsed as %+v", blocks)
+	}
+	// Items keep the blocks nested below them
+	item := parseMarkdown(strings.Split("- a\n\n  ```\n  code\n  ```", "\n"))[0].items[0]
+	if len(item.blocks) != 2 || item.blocks[1].kind != mdBlockCode {
+		t.Errorf("a list item with a code block parsed as %+v", item.blocks)
+	}
+}
+
+func TestMarkdownCodeBlocks(t *testing.T) {
+	for _, c := range []struct {
+		name, source string
+		want         []string
+	}{
+		{"info string", "```go\nfunc f() {\n\n\t*x* = 1\n}\n```", []string{"func f() {", "", "    *x* = 1", "}"}},
+		{"tildes", "~~~\n```\n~~~", []string{"```"}},
+		{"longer fence", "````\n```\n````", []string{"```"}},
+		{"indented fence", "  ```\n  a\n    b\n  ```", []string{"a", "  b"}},
+		{"unterminated", "```\nto the end", []string{"to the end"}},
+	} {
+		lines := strings.Split(c.source, "\n")
+		for i, line := range lines {
+			lines[i] = expandTabs(line)
+		}
+		blocks := parseMarkdown(lines)
+		if len(blocks) != 1 || blocks[0].kind != mdBlockCode || !reflect.DeepEqual(blocks[0].lines, c.want) {
+			t.Errorf("%s: parsed as %+v, want code lines %q", c.name, blocks, c.want)
+		}
+	}
+
+	// Code blocks are rendered verbatim, without inline styling
+	if got, want := renderMarkdown("```\n**not bold**\n```", nil), md(mdCodeBlock, "**not bold**"); got != want {
+		t.Errorf("a code block rendered %q, want %q", got, want)
+	}
+}
+
+func TestMarkdownBlocks(t *testing.T) {
+	for _, c := range []struct {
+		name, source, want string
+	}{
+		{"headings", "# One\n### Three ###", md("h1", "One") + "\n\n" + md(mdHeading, "Three")},
+		{"setext is a paragraph", "Title\n---", md(mdParagraph, "Title") + "\n\n" + md(mdRule, strings.Repeat("─", markdownRuleWidth))},
+		{"hard break", "a  \nb\\\nc\nd", md(mdParagraph, "a\nb\nc d")},
+		{"quote", "> a\nlazy\n\n> b", md(mdBlockquote, md(mdParagraph, "a lazy")) + "\n\n" + md(mdBlockquote, md(mdParagraph, "b"))},
+		{"CRLF", "one\r\n\r\ntwo", md(mdParagraph, "one") + "\n\n" + md(mdParagraph, "two")},
+	} {
+		if got := renderMarkdown(c.source, nil); got != c.want {
+			t.Errorf("%s: rendered %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	table := parseMarkdown(strings.Split("| a | b \\| c | d |\n|:--|:-:|--:|\n| 1 |", "\n"))
+	if len(table) != 1 || table[0].kind != mdBlockTable {
+		t.Fatalf("a pipe table parsed as %+v", table)
+	}
+	if got := table[0]; !reflect.DeepEqual(got.header, []string{"a", "b | c", "d"}) || !reflect.DeepEqual(got.rows, [][]string{{"1", "", ""}}) {
+		t.Errorf("the table parsed with header %q and rows %q", got.header, got.rows)
+	}
+	if got, want := table[0].align, []lipgloss.Position{lipgloss.Left, lipgloss.Center, lipgloss.Right}; !reflect.DeepEqual(got, want) {
+		t.Errorf("the table columns are aligned %v, want %v", got, want)
+	}
+}
@@ -22,7 +22,8 @@ const (
 )
 
 //export PositionTop
-func PositionTop() C.float {
+func PositionTop() (ret C.float) {
+	defer recoverValue("PositionTop", &ret, 0.0)
 	pos := Top
 	if err := Validate.Position(float64(pos), "top"); err != nil {
 		Log(LogLevelError, "PositionTop validation error: %v", err)
@@ -33,7 +34,8 @@ func PositionTop() C.float {
 }
 
 //export PositionBottom
-func PositionBottom() C.float {
+func PositionBottom() (ret C.float) {
+	defer recoverValue("PositionBottom", &ret, 1.0)
 	pos := Bottom
 	if err := Validate.Position(float64(pos), "bottom"); err != nil {
 		Log(LogLevelError, "PositionBottom validation error: %v", err)
@@ -44,7 +46,8 @@ func PositionBottom() C.float {
 }
 
 //export PositionCenter
-func PositionCenter() C.float {
+func PositionCenter() (ret C.float) {
+	defer recoverValue("PositionCenter", &ret, 0.5)
 	pos := Center
 	if err := Validate.Position(float64(pos), "center"); err != nil {
 		Log(LogLevelError, "PositionCenter validation error: %v", err)
@@ -55,7 +58,8 @@ func PositionCenter() C.float {
 }
 
 //export PositionLeft
-func PositionLeft() C.float {
+func PositionLeft() (ret C.float) {
+	defer recoverValue("PositionLeft", &ret, 0.0)
 	pos := Left
 	if err := Validate.Position(float64(pos), "left"); err != nil {
 		Log(LogLevelError, "PositionLeft validation error: %v", err)
@@ -66,7 +70,8 @@ func PositionLeft() C.float {
 }
 
 //export PositionRight
-func PositionRight() C.float {
+func PositionRight() (ret C.float) {
+	defer recoverValue("PositionRight", &ret, 1.0)
 	pos := Right
 	if err := Validate.Position(float64(pos), "right"); err != nil {
 		Log(LogLevelError, "PositionRight validation error: %v", err)
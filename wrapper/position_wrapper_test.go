@@ -0,0 +1,23 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import "testing"
+
+func TestPositions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		got  cFloat
+		want cFloat
+	}{
+		{"PositionTop", PositionTop(), 0},
+		{"PositionBottom", PositionBottom(), 1},
+		{"PositionCenter", PositionCenter(), 0.5},
+		{"PositionLeft", PositionLeft(), 0},
+		{"PositionRight", PositionRight(), 1},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s() = %v, want %v", tc.name, tc.got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// A panic must not unwind into C, where it would abort the host process.
+// Every export therefore defers one of the recover helpers below, which log
+// the panic and make the export return its documented failure value.
+
+// PanicError describes a panic recovered in an export
+type PanicError struct {
+	Op    string
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic in %s: %v", e.Op, e.Value)
+}
+
+// reportPanic logs a recovered panic along with the stack it was raised on
+func reportPanic(op string, value interface{}) {
+	err := &PanicError{Op: op, Value: value, Stack: debug.Stack()}
+	Log(LogLevelError, "%v\n%s", err, err.Stack)
+}
+
+// recoverPanic recovers a panic in an export whose failure value is the
+// zero value of its result, or which has none
+func recoverPanic(op string) {
+	if r := recover(); r != nil {
+		reportPanic(op, r)
+	}
+}
+
+// recoverValue recovers a panic in an export and sets its result to failure
+func recoverValue[T any](op string, ret *T, failure T) {
+	if r := recover(); r != nil {
+		reportPanic(op, r)
+		*ret = failure
+	}
+}
+
+// recoverString recovers a panic in an export returning a string and sets
+// its result to a copy of failure the caller frees as usual
+func recoverString(op string, ret **C.char, failure string) {
+	if r := recover(); r != nil {
+		reportPanic(op, r)
+		*ret = Memory.CString(failure, op+" result")
+	}
+}
+
+// recoverInto recovers a panic in a *_Into render export and reports the
+// failure the way intoError does
+func recoverInto(op string, ret *C.int, buf *C.char, capacity C.size_t, needed *C.size_t) {
+	if r := recover(); r != nil {
+		reportPanic(op, r)
+		*ret = intoError(buf, capacity, needed)
+	}
+}
+
+// recoverRGBA recovers a panic in an export returning color components and
+// sets them to opaque black
+func recoverRGBA(op string, r, g, b, a *C.uint32_t) {
+	if v := recover(); v != nil {
+		reportPanic(op, v)
+		*r, *g, *b, *a = 0, 0, 0, 0xFFFF
+	}
+}
@@ -0,0 +1,147 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// expectPanicLog fails the test unless fn logs a recovered panic in op
+// along with its stack trace
+func expectPanicLog(t *testing.T, op string, fn func()) {
+	t.Helper()
+	got := logged(fn)
+	if !strings.Contains(got, "panic in "+op+": ") {
+		t.Errorf("expected a recovered panic in %s to be logged, got %q", op, got)
+	}
+	if !strings.Contains(got, "goroutine ") || !strings.Contains(got, "recover.go") {
+		t.Errorf("the panic in %s was logged without a stack trace: %q", op, got)
+	}
+}
+
+// panickingStyle registers a style whose transform panics, as lipgloss
+// might on malformed input
+func panickingStyle(t *testing.T) cHandle {
+	style := lipgloss.NewStyle().Transform(func(string) string { panic("boom") })
+	id := styleReg.Register(&style)
+	t.Cleanup(func() { styleReg.Remove(id) })
+	return cHandle(id)
+}
+
+func TestExportsRecoverPanics(t *testing.T) {
+	boom := panickingStyle(t)
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+
+	expectPanicLog(t, "StyleRender", func() {
+		if got := render(t, boom, "x"); got != "" {
+			t.Errorf("StyleRender after a panic = %q, want \"\"", got)
+		}
+	})
+
+	buf := cBuffer(t, 16)
+	needed := cSize(99)
+	expectPanicLog(t, "StyleRenderInto", func() {
+		if got := StyleRenderInto(boom, cString(t, "x"), buf, 16, &needed); got != renderError {
+			t.Errorf("StyleRenderInto after a panic = %d, want RENDER_ERROR", got)
+		}
+	})
+	if needed != 0 || peekString(buf) != "" {
+		t.Errorf("StyleRenderInto after a panic left needed = %d and buf = %q", needed, peekString(buf))
+	}
+
+	// The pairs after the panicking one still get strings to free
+	in := newCRenderInputs([]uint64{uint64(bold), uint64(boom), uint64(bold)}, []string{"a", "b", "c"})
+	defer in.free()
+	expectPanicLog(t, "StyleRenderBatch", func() {
+		n, out := in.renderBatchStrings()
+		if n != 1 || out[0] != render(t, bold, "a") || out[1] != "" || out[2] != "" {
+			t.Errorf("StyleRenderBatch after a panic = %d, %q", n, out)
+		}
+	})
+
+	// A registry entry with a nil map, which the exports never create
+	broken := cHandle(listReg.Register(&listNode{items: []listItem{{value: "a"}}}))
+	defer FreeList(broken)
+	expectPanicLog(t, "ListSetItemChecked", func() {
+		if got := ListSetItemChecked(broken, 0, 1); got != 0 {
+			t.Errorf("ListSetItemChecked after a panic = %d, want 0", got)
+		}
+	})
+	expectPanicLog(t, "RenderList", func() {
+		if got := renderList(broken); got != "" {
+			t.Errorf("RenderList after a panic = %q, want \"\"", got)
+		}
+	})
+
+	// The library keeps working, and no registry lock was left held
+	if got, want := render(t, bold, "ok"), lipgloss.NewStyle().Bold(true).Render("ok"); got != want {
+		t.Errorf("StyleRender after the panics = %q, want %q", got, want)
+	}
+	if got := renderList(newTestList(t, "ok")); got != "• ok" {
+		t.Errorf("RenderList after the panics = %q", got)
+	}
+}
+
+// TestPanicCrossingFromC calls the exports from C, where an unrecovered
+// panic would abort the test binary
+func TestPanicCrossingFromC(t *testing.T) {
+	boom := panickingStyle(t)
+	in := newCRenderInputs([]uint64{uint64(boom), uint64(boom)}, []string{"a", "b"})
+	defer in.free()
+
+	expectPanicLog(t, "StyleRender", in.renderEach)
+	expectPanicLog(t, "StyleRenderBatch", func() {
+		if got := in.renderBatch(); got != 0 {
+			t.Errorf("renderBatch of panicking styles rendered %d pairs", got)
+		}
+	})
+}
+
+func TestRecoverHelpers(t *testing.T) {
+	toANSI := func() (ret cInt) {
+		defer recoverValue("toANSI", &ret, -1)
+		panic("boom")
+	}
+	expectPanicLog(t, "toANSI", func() {
+		if got := toANSI(); got != -1 {
+			t.Errorf("recoverValue set %d, want -1", got)
+		}
+	})
+
+	rgba := func() (r, g, b, a cUint32) {
+		defer recoverRGBA("rgba", &r, &g, &b, &a)
+		r, g, b = 1, 2, 3
+		panic("boom")
+	}
+	expectPanicLog(t, "rgba", func() {
+		if r, g, b, a := rgba(); r != 0 || g != 0 || b != 0 || a != 0xFFFF {
+			t.Errorf("recoverRGBA set %d, %d, %d, %d, want opaque black", r, g, b, a)
+		}
+	})
+
+	fallback := func() (ret *cChar) {
+		defer recoverString("fallback", &ret, "{}")
+		panic("boom")
+	}
+	expectPanicLog(t, "fallback", func() {
+		if got := takeString(fallback()); got != "{}" {
+			t.Errorf("recoverString set %q, want \"{}\"", got)
+		}
+	})
+
+	// Without a panic the helpers leave results alone
+	quiet := func() (ret cInt) {
+		defer recoverValue("quiet", &ret, -1)
+		return 7
+	}
+	if got := logged(func() {
+		if got := quiet(); got != 7 {
+			t.Errorf("recoverValue without a panic changed the result to %d", got)
+		}
+	}); got != "" {
+		t.Errorf("recoverValue without a panic logged %q", got)
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// renderCacheKey identifies one render. The style handle carries its
+// generation, so a freed and reused slot never matches an old entry, and
+// setters return new handles, so a changed style never matches either.
+type renderCacheKey struct {
+	style   uint64
+	profile termenv.Profile
+	dark    bool
+	input   string
+}
+
+type renderCacheEntry struct {
+	key    renderCacheKey
+	output string
+}
+
+// size is what an entry counts against the byte limit
+func (e *renderCacheEntry) size() int {
+	return len(e.key.input) + len(e.output)
+}
+
+// renderCacheStats counts cache activity since the cache was last enabled
+type renderCacheStats struct {
+	Enabled    bool   `json:"enabled"`
+	Entries    int    `json:"entries"`
+	Bytes      int    `json:"bytes"`
+	MaxEntries int    `json:"max_entries"`
+	MaxBytes   int    `json:"max_bytes"`
+	Hits       uint64 `json:"hits"`
+	Misses     uint64 `json:"misses"`
+	Evictions  uint64 `json:"evictions"`
+}
+
+// renderCacheLRU keeps recent StyleRender results, most recent first. It is
+// off until SetRenderCache gives it a size.
+type renderCacheLRU struct {
+	sync.Mutex
+	enabled    atomic.Bool
+	maxEntries int
+	maxBytes   int
+	bytes      int
+	order      *list.List
+	entries    map[renderCacheKey]*list.Element
+	byStyle    map[uint64]map[*list.Element]struct{}
+	hits       uint64
+	misses     uint64
+	evictions  uint64
+}
+
+var renderCache = &renderCacheLRU{}
+
+// reset drops every entry and sets new limits. The caller holds the lock.
+func (c *renderCacheLRU) reset(maxEntries, maxBytes int) {
+	c.maxEntries = maxEntries
+	c.maxBytes = maxBytes
+	c.bytes = 0
+	c.order = list.New()
+	c.entries = make(map[renderCacheKey]*list.Element)
+	c.byStyle = make(map[uint64]map[*list.Element]struct{})
+}
+
+// remove drops one entry. The caller holds the lock.
+func (c *renderCacheLRU) remove(el *list.Element) {
+	e := el.Value.(*renderCacheEntry)
+	c.order.Remove(el)
+	delete(c.entries, e.key)
+	if elems := c.byStyle[e.key.style]; elems != nil {
+		delete(elems, el)
+		if len(elems) == 0 {
+			delete(c.byStyle, e.key.style)
+		}
+	}
+	c.bytes -= e.size()
+}
+
+func (c *renderCacheLRU) get(key renderCacheKey) (string, bool) {
+	c.Lock()
+	defer c.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		c.hits++
+		return el.Value.(*renderCacheEntry).output, true
+	}
+	c.misses++
+	return "", false
+}
+
+// put stores a render and evicts the least recently used entries until the
+// cache is back within its limits
+func (c *renderCacheLRU) put(key renderCacheKey, output string) {
+	c.Lock()
+	defer c.Unlock()
+	if !c.enabled.Load() {
+		return
+	}
+	if el, ok := c.entries[key]; ok {
+		c.order.MoveToFront(el)
+		return
+	}
+
+	e := &renderCacheEntry{key: key, output: output}
+	if c.maxBytes > 0 && e.size() > c.maxBytes {
+		return
+	}
+	el := c.order.PushFront(e)
+	c.entries[key] = el
+	if c.byStyle[key.style] == nil {
+		c.byStyle[key.style] = make(map[*list.Element]struct{})
+	}
+	c.byStyle[key.style][el] = struct{}{}
+	c.bytes += e.size()
+
+	for c.order.Len() > c.maxEntries || (c.maxBytes > 0 && c.bytes > c.maxBytes) {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// invalidate drops every render of a style
+func (c *renderCacheLRU) invalidate(id uint64) {
+	if !c.enabled.Load() {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	for el := range c.byStyle[id] {
+		c.remove(el)
+	}
+}
+
+func (c *renderCacheLRU) stats() renderCacheStats {
+	c.Lock()
+	defer c.Unlock()
+	s := renderCacheStats{
+		Enabled:    c.enabled.Load(),
+		Bytes:      c.bytes,
+		MaxEntries: c.maxEntries,
+		MaxBytes:   c.maxBytes,
+		Hits:       c.hits,
+		Misses:     c.misses,
+		Evictions:  c.evictions,
+	}
+	if c.order != nil {
+		s.Entries = c.order.Len()
+	}
+	return s
+}
+
+// cachedRender renders str with the style behind id, going through the
+// render cache when it is enabled. Styles with a transform are not cached,
+// since the transform may not return the same output every time.
+func cachedRender(id uint64, style *lipgloss.Style, str string) string {
+	if !renderCache.enabled.Load() || style.GetTransform() != nil {
+		return style.Render(str)
+	}
+
+	key := renderCacheKey{
+		style:   id,
+		profile: lipgloss.ColorProfile(),
+		dark:    lipgloss.HasDarkBackground(),
+		input:   str,
+	}
+	if output, ok := renderCache.get(key); ok {
+		return output
+	}
+	output := style.Render(str)
+	renderCache.put(key, output)
+	return output
+}
+
+// SetRenderCache enables caching of StyleRender, StyleRenderInto and
+// StyleRenderBatch results for up to maxEntries renders taking at most
+// maxBytes of input and output text, 0 meaning no byte limit. Entries are
+// keyed by style, text and color profile, and dropped when their style is
+// freed. maxEntries of 0 disables the cache. Changing the limits empties
+// the cache and resets its counters.
+//
+//export SetRenderCache
+func SetRenderCache(maxEntries C.int, maxBytes C.size_t) {
+	defer recoverPanic("SetRenderCache")
+	if maxEntries < 0 {
+		Log(LogLevelError, "SetRenderCache received a negative entry limit: %d", int(maxEntries))
+		return
+	}
+
+	renderCache.Lock()
+	defer renderCache.Unlock()
+	renderCache.reset(int(maxEntries), int(maxBytes))
+	renderCache.hits, renderCache.misses, renderCache.evictions = 0, 0, 0
+	renderCache.enabled.Store(maxEntries > 0)
+	Log(LogLevelDebug, "Set render cache to %d entries, %d bytes", int(maxEntries), uint64(maxBytes))
+}
+
+// ClearRenderCache drops every cached render, keeping the limits and
+// counters.
+//
+//export ClearRenderCache
+func ClearRenderCache() {
+	defer recoverPanic("ClearRenderCache")
+	renderCache.Lock()
+	defer renderCache.Unlock()
+	renderCache.reset(renderCache.maxEntries, renderCache.maxBytes)
+}
+
+// GetRenderCacheStats describes the size and hit rate of the render cache.
+//
+//export GetRenderCacheStats
+func GetRenderCacheStats() (ret *C.char) {
+	defer recoverString("GetRenderCacheStats", &ret, "")
+	s := renderCache.stats()
+	if !s.Enabled {
+		return Memory.CString("Render cache disabled", "render cache stats string")
+	}
+	return Memory.CString(fmt.Sprintf("Render cache: %d/%d entries, %d bytes, %d hits, %d misses, %d evictions",
+		s.Entries, s.MaxEntries, s.Bytes, s.Hits, s.Misses, s.Evictions), "render cache stats string")
+}
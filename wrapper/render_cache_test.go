@@ -0,0 +1,88 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+)
+
+// withRenderCache enables the render cache and disables it again when the
+// test ends
+func withRenderCache(t *testing.T, maxEntries int, maxBytes int) {
+	SetRenderCache(cInt(maxEntries), cSize(maxBytes))
+	t.Cleanup(func() { SetRenderCache(0, 0) })
+}
+
+func cacheStats() string {
+	return takeString(GetRenderCacheStats())
+}
+
+func TestRenderCache(t *testing.T) {
+	if got := cacheStats(); got != "Render cache disabled" {
+		t.Fatalf("GetRenderCacheStats before enabling = %q", got)
+	}
+	withRenderCache(t, 2, 0)
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+
+	first := render(t, bold, "a")
+	if got := render(t, bold, "a"); got != first {
+		t.Errorf("a cached render returned %q, want %q", got, first)
+	}
+	render(t, bold, "b")
+	render(t, bold, "c")
+	if got, want := cacheStats(), "Render cache: 2/2 entries, 20 bytes, 1 hits, 3 misses, 1 evictions"; got != want {
+		t.Errorf("GetRenderCacheStats = %q, want %q", got, want)
+	}
+
+	// Freeing a style drops its renders
+	italic := StyleItalic(bold, 1)
+	render(t, italic, "a")
+	FreeStyle(italic)
+	if s := renderCache.stats(); s.Entries != 1 {
+		t.Errorf("after freeing a style the cache holds %d entries, want 1", s.Entries)
+	}
+
+	// Changing the profile misses instead of returning stale escapes
+	t.Cleanup(func() { SetColorProfile(cString(t, "truecolor")) })
+	SetColorProfile(cString(t, "ascii"))
+	if got := render(t, bold, "c"); got != "c" {
+		t.Errorf("with the ascii profile the cache returned %q", got)
+	}
+
+	ClearRenderCache()
+	if got, want := cacheStats(), "Render cache: 0/2 entries, 0 bytes, 1 hits, 5 misses, 2 evictions"; got != want {
+		t.Errorf("GetRenderCacheStats after ClearRenderCache = %q, want %q", got, want)
+	}
+
+	expectLog(t, "negative entry limit", func() { SetRenderCache(-1, 0) })
+	if !renderCache.enabled.Load() {
+		t.Error("a negative limit disabled the cache")
+	}
+}
+
+func TestRenderCacheByteLimit(t *testing.T) {
+	withRenderCache(t, 10, 4)
+	plain := keep(t, NewStyle())
+
+	render(t, plain, "ab")
+	render(t, plain, "cd")
+	if s := renderCache.stats(); s.Entries != 1 || s.Bytes != 4 || s.Evictions != 1 {
+		t.Errorf("with a 4 byte limit the cache holds %+v", s)
+	}
+	// Renders larger than the whole cache are not stored
+	render(t, plain, "efg")
+	if s := renderCache.stats(); s.Entries != 1 || s.Evictions != 1 {
+		t.Errorf("an oversized render changed the cache to %+v", s)
+	}
+}
+
+func TestRenderCacheSkipsTransforms(t *testing.T) {
+	withRenderCache(t, 10, 0)
+	upper := keep(t, StyleTransform(keep(t, NewStyle()), upperTransformFunc(), nil))
+	if got := render(t, upper, "abc"); got != "ABC" {
+		t.Errorf("a transformed render = %q", got)
+	}
+	if s := renderCache.stats(); s.Entries != 0 || s.Misses != 0 {
+		t.Errorf("a style with a transform went through the cache: %+v", s)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import "unsafe"
+
+// writeInto copies result and a NUL terminator into buf when they fit in
+// capacity bytes. The required size is reported through needed either way,
+// so a caller can grow its buffer and retry.
+func writeInto(result string, buf *C.char, capacity C.size_t, needed *C.size_t) C.int {
+	size := len(result) + 1
+	if needed != nil {
+		*needed = C.size_t(size)
+	}
+	if buf == nil || uint64(capacity) < uint64(size) {
+		return C.RENDER_BUFFER_TOO_SMALL
+	}
+
+	dst := unsafe.Slice((*byte)(unsafe.Pointer(buf)), size)
+	copy(dst, result)
+	dst[size-1] = 0
+	return C.RENDER_OK
+}
+
+// intoError reports a failed render, leaving an empty string in buf
+func intoError(buf *C.char, capacity C.size_t, needed *C.size_t) C.int {
+	if needed != nil {
+		*needed = 0
+	}
+	if buf != nil && capacity > 0 {
+		*buf = 0
+	}
+	return C.RENDER_ERROR
+}
+
+// StyleRenderInto renders str like StyleRender, writing the result into
+// the caller's buffer instead of allocating one.
+//
+//export StyleRenderInto
+func StyleRenderInto(id C.uint64_t, str *C.char, buf *C.char, capacity C.size_t, needed *C.size_t) (ret C.int) {
+	defer recoverInto("StyleRenderInto", &ret, buf, capacity, needed)
+	result, err := renderStyle(uint64(id), String.GoString(str))
+	if err != nil {
+		Log(LogLevelError, "StyleRenderInto error: %v", err)
+		return intoError(buf, capacity, needed)
+	}
+	return writeInto(result, buf, capacity, needed)
+}
+
+// JoinHorizontalInto joins two blocks like JoinHorizontal, writing the
+// result into the caller's buffer.
+//
+//export JoinHorizontalInto
+func JoinHorizontalInto(pos C.double, str1 *C.char, str2 *C.char, buf *C.char, capacity C.size_t, needed *C.size_t) (ret C.int) {
+	defer recoverInto("JoinHorizontalInto", &ret, buf, capacity, needed)
+	joined, err := joinHorizontal(float64(pos), String.GoString(str1), String.GoString(str2))
+	if err != nil {
+		Log(LogLevelError, "JoinHorizontalInto position error: %v", err)
+		return intoError(buf, capacity, needed)
+	}
+	return writeInto(joined, buf, capacity, needed)
+}
+
+// PlaceInto places str like Place, writing the result into the caller's
+// buffer.
+//
+//export PlaceInto
+func PlaceInto(width, height C.int, hPos, vPos C.double, str *C.char, buf *C.char, capacity C.size_t, needed *C.size_t) (ret C.int) {
+	defer recoverInto("PlaceInto", &ret, buf, capacity, needed)
+	placed, err := place(int(width), int(height), float64(hPos), float64(vPos), String.GoString(str))
+	if err != nil {
+		Log(LogLevelError, "PlaceInto validation error: %v", err)
+		return intoError(buf, capacity, needed)
+	}
+	return writeInto(placed, buf, capacity, needed)
+}
+
+// RenderTableInto renders a table like RenderTable, writing the result
+// into the caller's buffer.
+//
+//export RenderTableInto
+func RenderTableInto(id C.uint64_t, buf *C.char, capacity C.size_t, needed *C.size_t) (ret C.int) {
+	defer recoverInto("RenderTableInto", &ret, buf, capacity, needed)
+	result, err := renderTable(uint64(id))
+	if err != nil {
+		Log(LogLevelError, "RenderTableInto error: %v", err)
+		return intoError(buf, capacity, needed)
+	}
+	return writeInto(result, buf, capacity, needed)
+}
@@ -0,0 +1,91 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import "testing"
+
+func TestRenderInto(t *testing.T) {
+	style := keep(t, StylePadding(keep(t, NewStyle()), 0, 1, 0, 1))
+	str := cString(t, "Buffered")
+	var needed cSize
+
+	small := cBuffer(t, 4)
+	if got := StyleRenderInto(style, str, small, 4, &needed); got != renderBufferTooSmall {
+		t.Errorf("StyleRenderInto into 4 bytes = %d, want RENDER_BUFFER_TOO_SMALL", got)
+	}
+	if needed != 11 {
+		t.Errorf("StyleRenderInto needs %d bytes, want 11", needed)
+	}
+	if got := peekString(small); got != "" {
+		t.Errorf("a failed StyleRenderInto wrote %q", got)
+	}
+
+	buf := cBuffer(t, int(needed))
+	if got := StyleRenderInto(style, str, buf, needed, nil); got != renderOK {
+		t.Errorf("StyleRenderInto into an exact fit = %d, want RENDER_OK", got)
+	}
+	if got := peekString(buf); got != " Buffered " {
+		t.Errorf("StyleRenderInto wrote %q", got)
+	}
+
+	expectLog(t, "StyleRenderInto error", func() {
+		if got := StyleRenderInto(stale(), str, buf, needed, &needed); got != renderError {
+			t.Errorf("StyleRenderInto with a freed style = %d, want RENDER_ERROR", got)
+		}
+	})
+	if needed != 0 || peekString(buf) != "" {
+		t.Errorf("a failed StyleRenderInto left needed=%d and %q", needed, peekString(buf))
+	}
+}
+
+func TestLayoutInto(t *testing.T) {
+	buf := cBuffer(t, 64)
+	var needed cSize
+
+	if got := JoinHorizontalInto(0, cString(t, "a"), cString(t, "b\nc"), buf, 64, &needed); got != renderOK {
+		t.Errorf("JoinHorizontalInto = %d", got)
+	}
+	if got := peekString(buf); got != "ab\n c" || needed != 6 {
+		t.Errorf("JoinHorizontalInto wrote %q, needed %d", got, needed)
+	}
+	if got := PlaceInto(3, 1, 0.5, 0, cString(t, "x"), nil, 0, &needed); got != renderBufferTooSmall || needed != 4 {
+		t.Errorf("PlaceInto size query = %d, needed %d", got, needed)
+	}
+	if got := PlaceInto(3, 1, 0.5, 0, cString(t, "x"), buf, 64, nil); got != renderOK || peekString(buf) != " x " {
+		t.Errorf("PlaceInto = %d, wrote %q", got, peekString(buf))
+	}
+
+	expectLog(t, "JoinHorizontalInto position error", func() {
+		if got := JoinHorizontalInto(-1, nil, nil, buf, 64, nil); got != renderError {
+			t.Errorf("JoinHorizontalInto with an invalid position = %d", got)
+		}
+	})
+	expectLog(t, "PlaceInto validation error", func() {
+		if got := PlaceInto(-3, 1, 0, 0, nil, buf, 64, nil); got != renderError {
+			t.Errorf("PlaceInto with a negative width = %d", got)
+		}
+	})
+}
+
+func TestRenderTableInto(t *testing.T) {
+	table := newTestTable(t)
+	var needed cSize
+	if got := RenderTableInto(table, nil, 0, &needed); got != renderBufferTooSmall {
+		t.Fatalf("RenderTableInto size query = %d", got)
+	}
+	buf := cBuffer(t, int(needed))
+	if got := RenderTableInto(table, buf, needed, nil); got != renderOK {
+		t.Fatalf("RenderTableInto = %d", got)
+	}
+	if got, want := peekString(buf), takeString(RenderTable(table)); got != want {
+		t.Errorf("RenderTableInto wrote %q, want %q", got, want)
+	}
+
+	freed := NewTable()
+	FreeTable(freed)
+	expectLog(t, "RenderTableInto error", func() {
+		if got := RenderTableInto(freed, nil, 0, nil); got != renderError {
+			t.Errorf("RenderTableInto of a freed table = %d", got)
+		}
+	})
+}
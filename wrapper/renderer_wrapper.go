@@ -80,12 +80,14 @@ func validateRenderer(op string) error {
 
 //export DefaultRenderer
 func DefaultRenderer() {
+	defer recoverPanic("DefaultRenderer")
 	setRenderer(lipgloss.DefaultRenderer(), os.Stdout)
 	Log(LogLevelDebug, "Initialized default renderer with stdout")
 }
 
 //export NewRenderer
 func NewRenderer(w *C.FILE) {
+	defer recoverPanic("NewRenderer")
 	if w == nil {
 		Log(LogLevelError, "NewRenderer received nil file pointer")
 		return
@@ -103,10 +105,11 @@ func NewRenderer(w *C.FILE) {
 }
 
 //export RendererColorProfile
-func RendererColorProfile() *C.char {
+func RendererColorProfile() (ret *C.char) {
+	defer recoverString("RendererColorProfile", &ret, "ascii")
 	if err := validateRenderer("color-profile"); err != nil {
 		Log(LogLevelError, "RendererColorProfile error: %v", err)
-		return C.CString("ascii") // Safe default
+		return Memory.CString("ascii", "color profile string") // Safe default
 	}
 
 	renderer := GetRenderer()
@@ -129,7 +132,7 @@ func RendererColorProfile() *C.char {
 	cs, err := String.CString(profileStr)
 	if err != nil {
 		Log(LogLevelError, "RendererColorProfile memory allocation error: %v", err)
-		return C.CString("ascii")
+		return Memory.CString("ascii", "color profile string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "color profile string")
@@ -138,6 +141,7 @@ func RendererColorProfile() *C.char {
 
 //export RendererHasDarkBackground
 func RendererHasDarkBackground() C.bool {
+	defer recoverPanic("RendererHasDarkBackground")
 	if err := validateRenderer("dark-background"); err != nil {
 		Log(LogLevelError, "RendererHasDarkBackground error: %v", err)
 		return C.bool(false)
@@ -149,6 +153,7 @@ func RendererHasDarkBackground() C.bool {
 
 //export RendererNewStyle
 func RendererNewStyle() unsafe.Pointer {
+	defer recoverPanic("RendererNewStyle")
 	renderer := GetRenderer()
 	if renderer == nil {
 		Log(LogLevelWarn, "No renderer available, using default")
@@ -162,29 +167,30 @@ func RendererNewStyle() unsafe.Pointer {
 }
 
 //export RendererPlace
-func RendererPlace(width, height C.int, hPos, vPos C.double, str *C.char) *C.char {
+func RendererPlace(width, height C.int, hPos, vPos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("RendererPlace", &ret, "")
 	if err := validateRenderer("place"); err != nil {
 		Log(LogLevelError, "RendererPlace error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "placed string")
 	}
 	if err := Validate.Dimension(int(width), "place width"); err != nil {
 		Log(LogLevelError, "RendererPlace width validation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "placed string")
 		return defaultCs
 	}
 	if err := Validate.Dimension(int(height), "place height"); err != nil {
 		Log(LogLevelError, "RendererPlace height validation error: %v", err)
-		defaultCs, _ := String.CString("")
+		defaultCs := Memory.CString("", "placed string")
 		return defaultCs
 	}
 	if err := Validate.Position(float64(hPos), "horizontal"); err != nil {
 		Log(LogLevelError, "RendererPlace horizontal position error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "placed string")
 	}
 
 	if err := Validate.Position(float64(vPos), "vertical"); err != nil {
 		Log(LogLevelError, "RendererPlace vertical position error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "placed string")
 	}
 
 	renderer := GetRenderer()
@@ -195,7 +201,7 @@ func RendererPlace(width, height C.int, hPos, vPos C.double, str *C.char) *C.cha
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "RendererPlace memory allocation error: %v", err)
-		return C.CString(goStr)
+		return Memory.CString(goStr, "placed string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "placed string")
@@ -203,20 +209,21 @@ func RendererPlace(width, height C.int, hPos, vPos C.double, str *C.char) *C.cha
 }
 
 //export RendererPlaceHorizontal
-func RendererPlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
+func RendererPlaceHorizontal(width C.int, pos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("RendererPlaceHorizontal", &ret, "")
 	if err := validateRenderer("place-horizontal"); err != nil {
 		Log(LogLevelError, "RendererPlaceHorizontal error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "horizontally placed string")
 	}
 
 	if err := Validate.Dimension(int(width), "place-horizontal"); err != nil {
 		Log(LogLevelError, "RendererPlaceHorizontal width error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "horizontally placed string")
 	}
 
 	if err := Validate.Position(float64(pos), "horizontal"); err != nil {
 		Log(LogLevelError, "RendererPlaceHorizontal position error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "horizontally placed string")
 	}
 
 	renderer := GetRenderer()
@@ -226,7 +233,7 @@ func RendererPlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "RendererPlaceHorizontal memory allocation error: %v", err)
-		return C.CString(goStr)
+		return Memory.CString(goStr, "horizontally placed string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "horizontally placed string")
@@ -234,20 +241,21 @@ func RendererPlaceHorizontal(width C.int, pos C.double, str *C.char) *C.char {
 }
 
 //export RendererPlaceVertical
-func RendererPlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
+func RendererPlaceVertical(height C.int, pos C.double, str *C.char) (ret *C.char) {
+	defer recoverString("RendererPlaceVertical", &ret, "")
 	if err := validateRenderer("place-vertical"); err != nil {
 		Log(LogLevelError, "RendererPlaceVertical error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "vertically placed string")
 	}
 
 	if err := Validate.Dimension(int(height), "place-vertical"); err != nil {
 		Log(LogLevelError, "RendererPlaceVertical height error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "vertically placed string")
 	}
 
 	if err := Validate.Position(float64(pos), "vertical"); err != nil {
 		Log(LogLevelError, "RendererPlaceVertical position error: %v", err)
-		return C.CString(String.GoString(str))
+		return Memory.CString(String.GoString(str), "vertically placed string")
 	}
 
 	renderer := GetRenderer()
@@ -257,7 +265,7 @@ func RendererPlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
 	cs, err := String.CString(placed)
 	if err != nil {
 		Log(LogLevelError, "RendererPlaceVertical memory allocation error: %v", err)
-		return C.CString(goStr)
+		return Memory.CString(goStr, "vertically placed string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "vertically placed string")
@@ -266,6 +274,7 @@ func RendererPlaceVertical(height C.int, pos C.double, str *C.char) *C.char {
 
 //export RendererSetColorProfile
 func RendererSetColorProfile(p *C.char) {
+	defer recoverPanic("RendererSetColorProfile")
 	if err := validateRenderer("set-color-profile"); err != nil {
 		Log(LogLevelError, "RendererSetColorProfile error: %v", err)
 		return
@@ -295,6 +304,7 @@ func RendererSetColorProfile(p *C.char) {
 
 //export RendererSetHasDarkBackground
 func RendererSetHasDarkBackground(b C.bool) {
+	defer recoverPanic("RendererSetHasDarkBackground")
 	if err := validateRenderer("set-dark-background"); err != nil {
 		Log(LogLevelError, "RendererSetHasDarkBackground error: %v", err)
 		return
@@ -307,6 +317,7 @@ func RendererSetHasDarkBackground(b C.bool) {
 
 //export RendererSetOutput
 func RendererSetOutput(o *C.FILE) {
+	defer recoverPanic("RendererSetOutput")
 	if err := validateRenderer("set-output"); err != nil {
 		Log(LogLevelError, "RendererSetOutput error: %v", err)
 		return
@@ -0,0 +1,138 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// withTestRenderer makes a renderer writing to /dev/null the active
+// renderer. Not being a terminal, it starts out with the ascii profile.
+func withTestRenderer(t *testing.T) {
+	NewRenderer(devNull())
+	if GetRenderer() == nil {
+		t.Fatal("NewRenderer did not set a renderer")
+	}
+	t.Cleanup(func() { setRenderer(nil, nil) })
+}
+
+func TestRenderer(t *testing.T) {
+	withTestRenderer(t)
+	if got := takeString(RendererColorProfile()); got != "ascii" {
+		t.Errorf("RendererColorProfile of a non-terminal = %q, want ascii", got)
+	}
+
+	for _, profile := range []string{"ansi", "ansi256", "truecolor", "ascii"} {
+		RendererSetColorProfile(cString(t, profile))
+		if got := takeString(RendererColorProfile()); got != profile {
+			t.Errorf("RendererColorProfile after setting %s = %q", profile, got)
+		}
+	}
+	expectLog(t, "Invalid color profile specified: sepia", func() { RendererSetColorProfile(cString(t, "sepia")) })
+
+	RendererSetHasDarkBackground(true)
+	if !RendererHasDarkBackground() {
+		t.Error("RendererHasDarkBackground() = false after setting it")
+	}
+	RendererSetHasDarkBackground(false)
+	if RendererHasDarkBackground() {
+		t.Error("RendererHasDarkBackground() = true after clearing it")
+	}
+
+	RendererSetOutput(devNull())
+	expectLog(t, "RendererSetOutput received nil file pointer", func() { RendererSetOutput(nil) })
+
+	style := (*lipgloss.Style)(RendererNewStyle())
+	if got := style.Bold(true).Render("x"); got != "x" {
+		t.Errorf("a style of the ascii renderer renders %q, want plain text", got)
+	}
+
+	// The global profile pinned by TestMain is not affected
+	if got := takeString(ColorProfile()); got != "truecolor" {
+		t.Errorf("ColorProfile() = %q after changing the renderer", got)
+	}
+}
+
+func TestRendererPlace(t *testing.T) {
+	withTestRenderer(t)
+	box := cString(t, "x")
+
+	if got := takeString(RendererPlace(3, 2, 1, 0, box)); got != "  x\n   " {
+		t.Errorf("RendererPlace = %q", got)
+	}
+	if got := takeString(RendererPlaceHorizontal(3, 0.5, box)); got != " x " {
+		t.Errorf("RendererPlaceHorizontal = %q", got)
+	}
+	if got := takeString(RendererPlaceVertical(3, 1, box)); got != " \n \nx" {
+		t.Errorf("RendererPlaceVertical = %q", got)
+	}
+
+	// Invalid sizes give an empty string for RendererPlace and the input
+	// unchanged for the others, as do invalid positions
+	for _, c := range []struct {
+		name string
+		fn   func() *cChar
+		want string
+	}{
+		{"RendererPlace width", func() *cChar { return RendererPlace(-1, 2, 0, 0, box) }, ""},
+		{"RendererPlace height", func() *cChar { return RendererPlace(2, -1, 0, 0, box) }, ""},
+		{"RendererPlace horizontal position", func() *cChar { return RendererPlace(2, 2, 2, 0, box) }, "x"},
+		{"RendererPlace vertical position", func() *cChar { return RendererPlace(2, 2, 0, -1, box) }, "x"},
+		{"RendererPlaceHorizontal width", func() *cChar { return RendererPlaceHorizontal(-1, 0, box) }, "x"},
+		{"RendererPlaceHorizontal position", func() *cChar { return RendererPlaceHorizontal(2, 2, box) }, "x"},
+		{"RendererPlaceVertical height", func() *cChar { return RendererPlaceVertical(-1, 0, box) }, "x"},
+		{"RendererPlaceVertical position", func() *cChar { return RendererPlaceVertical(2, 2, box) }, "x"},
+	} {
+		var got string
+		expectLog(t, c.name, func() { got = takeString(c.fn()) })
+		if got != c.want {
+			t.Errorf("%s error returned %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNoRenderer(t *testing.T) {
+	setRenderer(nil, nil)
+	box := cString(t, "x")
+
+	for _, c := range []struct {
+		name string
+		fn   func() *cChar
+		want string
+	}{
+		{"RendererColorProfile", RendererColorProfile, "ascii"},
+		{"RendererPlace", func() *cChar { return RendererPlace(3, 3, 0, 0, box) }, "x"},
+		{"RendererPlaceHorizontal", func() *cChar { return RendererPlaceHorizontal(3, 0, box) }, "x"},
+		{"RendererPlaceVertical", func() *cChar { return RendererPlaceVertical(3, 0, box) }, "x"},
+	} {
+		var got string
+		expectLog(t, "no renderer available", func() { got = takeString(c.fn()) })
+		if got != c.want {
+			t.Errorf("%s without a renderer = %q, want %q", c.name, got, c.want)
+		}
+	}
+	for name, fn := range map[string]func(){
+		"RendererHasDarkBackground":    func() { RendererHasDarkBackground() },
+		"RendererSetColorProfile":      func() { RendererSetColorProfile(cString(t, "ansi")) },
+		"RendererSetHasDarkBackground": func() { RendererSetHasDarkBackground(true) },
+		"RendererSetOutput":            func() { RendererSetOutput(nil) },
+	} {
+		if got := logged(fn); !strings.Contains(got, name+" error") {
+			t.Errorf("%s without a renderer logged %q", name, got)
+		}
+	}
+	expectLog(t, "NewRenderer received nil file pointer", func() { NewRenderer(nil) })
+
+	// RendererNewStyle falls back to the default renderer
+	t.Cleanup(func() { setRenderer(nil, nil) })
+	if style := RendererNewStyle(); style == unsafe.Pointer(nil) {
+		t.Fatal("RendererNewStyle returned NULL")
+	}
+	if GetRenderer() != lipgloss.DefaultRenderer() {
+		t.Error("RendererNewStyle did not install the default renderer")
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include <pthread.h>
+#include "lipgloss_types.h"
+
+static inline uint64_t currentThreadID(void) {
+	return (uint64_t)(uintptr_t)pthread_self();
+}
+*/
+import "C"
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// scope collects the handles and strings allocated on one thread between
+// BeginScope and EndScope
+type scope struct {
+	id      uint64
+	thread  uint64
+	handles []uint64
+	strings map[unsafe.Pointer]struct{}
+}
+
+// scopeRegistry keeps a stack of open scopes per calling thread. Exports
+// run on the C thread that called them, so pthread_self identifies the
+// caller.
+type scopeRegistry struct {
+	sync.Mutex
+	nextID  uint64
+	open    atomic.Int64
+	threads map[uint64][]*scope
+	strings map[unsafe.Pointer]*scope
+}
+
+var scopes = &scopeRegistry{
+	threads: make(map[uint64][]*scope),
+	strings: make(map[unsafe.Pointer]*scope),
+}
+
+// current returns the innermost open scope of the calling thread. The
+// caller holds the lock.
+func (r *scopeRegistry) current() *scope {
+	stack := r.threads[uint64(C.currentThreadID())]
+	if len(stack) == 0 {
+		return nil
+	}
+	return stack[len(stack)-1]
+}
+
+// adoptHandle puts a new handle in the calling thread's innermost scope
+func (r *scopeRegistry) adoptHandle(id uint64) {
+	if r.open.Load() == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if s := r.current(); s != nil {
+		s.handles = append(s.handles, id)
+	}
+}
+
+// adoptString puts a new C string in the calling thread's innermost scope
+func (r *scopeRegistry) adoptString(ptr unsafe.Pointer) {
+	if r.open.Load() == 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if s := r.current(); s != nil {
+		s.strings[ptr] = struct{}{}
+		r.strings[ptr] = s
+	}
+}
+
+// forgetString drops a string that is being freed, or escapes, from the
+// scope holding it
+func (r *scopeRegistry) forgetString(ptr unsafe.Pointer) bool {
+	if r.open.Load() == 0 {
+		return false
+	}
+	r.Lock()
+	defer r.Unlock()
+	s, ok := r.strings[ptr]
+	if ok {
+		delete(s.strings, ptr)
+		delete(r.strings, ptr)
+	}
+	return ok
+}
+
+// escapeHandle removes a handle from every open scope of the calling
+// thread
+func (r *scopeRegistry) escapeHandle(id uint64) bool {
+	if r.open.Load() == 0 {
+		return false
+	}
+	r.Lock()
+	defer r.Unlock()
+	found := false
+	for _, s := range r.threads[uint64(C.currentThreadID())] {
+		for i, h := range s.handles {
+			if h == id {
+				s.handles = append(s.handles[:i], s.handles[i+1:]...)
+				found = true
+				break
+			}
+		}
+	}
+	return found
+}
+
+// freeHandle releases a handle of any kind if it is still live. Handles
+// already freed by hand are skipped; their generation no longer matches.
+func freeHandle(id uint64) {
+	if checkHandle(id, "end-scope") != nil {
+		return
+	}
+	kind, _, _ := splitHandle(id)
+	switch kind {
+	case handleKindStyle:
+		styleReg.Remove(id)
+	case handleKindTable:
+		tableReg.Remove(id)
+	case handleKindTree:
+		treeReg.Remove(id)
+	case handleKindList:
+		listReg.Remove(id)
+	case handleKindTheme:
+		themeReg.Remove(id)
+	}
+}
+
+// BeginScope opens a scope on the calling thread and returns its ID. Until
+// the matching EndScope, every handle and string this thread allocates
+// belongs to the scope. Scopes nest; allocations go to the innermost one.
+//
+//export BeginScope
+func BeginScope() C.uint64_t {
+	defer recoverPanic("BeginScope")
+	scopes.Lock()
+	defer scopes.Unlock()
+
+	scopes.nextID++
+	s := &scope{
+		id:      scopes.nextID,
+		thread:  uint64(C.currentThreadID()),
+		strings: make(map[unsafe.Pointer]struct{}),
+	}
+	scopes.threads[s.thread] = append(scopes.threads[s.thread], s)
+	scopes.open.Add(1)
+	Log(LogLevelDebug, "Began scope %d on thread %#x", s.id, s.thread)
+	return C.uint64_t(s.id)
+}
+
+// EndScope frees every handle and string still owned by the scope, most
+// recent first, and closes it. Scopes opened inside it are ended as well.
+// It must be called on the thread that began the scope.
+//
+//export EndScope
+func EndScope(id C.uint64_t) {
+	defer recoverPanic("EndScope")
+	thread := uint64(C.currentThreadID())
+
+	scopes.Lock()
+	stack := scopes.threads[thread]
+	depth := -1
+	for i, s := range stack {
+		if s.id == uint64(id) {
+			depth = i
+			break
+		}
+	}
+	if depth < 0 {
+		scopes.Unlock()
+		Log(LogLevelError, "EndScope: scope %d is not open on this thread", uint64(id))
+		return
+	}
+
+	ended := stack[depth:]
+	if depth == 0 {
+		delete(scopes.threads, thread)
+	} else {
+		scopes.threads[thread] = stack[:depth]
+	}
+	var strs []unsafe.Pointer
+	var handles []uint64
+	for i := len(ended) - 1; i >= 0; i-- {
+		s := ended[i]
+		for ptr := range s.strings {
+			delete(scopes.strings, ptr)
+			strs = append(strs, ptr)
+		}
+		for j := len(s.handles) - 1; j >= 0; j-- {
+			handles = append(handles, s.handles[j])
+		}
+	}
+	scopes.open.Add(-int64(len(ended)))
+	scopes.Unlock()
+
+	// Free outside the scope lock; the registries take their own locks
+	for _, ptr := range strs {
+		FreeString((*C.char)(ptr))
+	}
+	for _, h := range handles {
+		freeHandle(h)
+	}
+	Log(LogLevelDebug, "Ended scope %d: freed %d strings and up to %d handles", uint64(id), len(strs), len(handles))
+}
+
+// ScopeEscape takes a handle out of the calling thread's scopes so it
+// survives EndScope. The caller becomes responsible for freeing it.
+// Returns 1 if the handle was in a scope.
+//
+//export ScopeEscape
+func ScopeEscape(id C.uint64_t) C.int {
+	defer recoverPanic("ScopeEscape")
+	return String.ToCInt(scopes.escapeHandle(uint64(id)))
+}
+
+// ScopeEscapeString takes a string out of its scope so it survives
+// EndScope. The caller becomes responsible for freeing it with FreeString.
+// Returns 1 if the string was in a scope.
+//
+//export ScopeEscapeString
+func ScopeEscapeString(str *C.char) C.int {
+	defer recoverPanic("ScopeEscapeString")
+	return String.ToCInt(scopes.forgetString(unsafe.Pointer(str)))
+}
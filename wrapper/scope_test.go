@@ -0,0 +1,72 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// Scopes belong to the calling thread, so the tests pin their goroutine
+// to one, like a C caller
+func lockThread(t *testing.T) {
+	runtime.LockOSThread()
+	t.Cleanup(runtime.UnlockOSThread)
+}
+
+func TestScopes(t *testing.T) {
+	lockThread(t)
+	before := GetLiveHandleCount()
+
+	outer := BeginScope()
+	title := StyleBold(NewStyle(), 1)
+	kept := NewStyle()
+	if ScopeEscape(kept) != 1 {
+		t.Error("ScopeEscape of a scoped handle returned 0")
+	}
+	header := StyleRender(title, cString(t, "Dashboard"))
+	if ScopeEscapeString(header) != 1 {
+		t.Error("ScopeEscapeString of a scoped string returned 0")
+	}
+	inner := BeginScope()
+	NewTable()
+	FreeStyle(title) // freed by hand before EndScope
+	EndScope(outer)  // ends inner too
+
+	if got := GetLiveHandleCount(); got != before+1 {
+		t.Errorf("after EndScope %d handles are live, want %d", got, before+1)
+	}
+	if got := takeString(header); got == "" {
+		t.Error("an escaped string was freed")
+	}
+	FreeStyle(kept)
+
+	expectLog(t, "not open on this thread", func() { EndScope(inner) })
+	if ScopeEscape(kept) != 0 || ScopeEscapeString(nil) != 0 {
+		t.Error("escaping outside any scope returned 1")
+	}
+}
+
+func TestScopesArePerThread(t *testing.T) {
+	lockThread(t)
+	scope := BeginScope()
+	defer EndScope(scope)
+
+	var other cHandle
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		other = NewStyle()
+		expectLog(t, "not open on this thread", func() { EndScope(scope) })
+	}()
+	wg.Wait()
+
+	if ScopeEscape(other) != 0 {
+		t.Error("a handle made on another thread joined this thread's scope")
+	}
+	FreeStyle(other)
+}
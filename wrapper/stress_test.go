@@ -0,0 +1,124 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// The stress tests hammer the registries from many goroutines and are meant
+// to be run with -race. Styles are immutable once registered, so they are
+// shared; tables, trees and lists are not safe for concurrent mutation, so
+// every goroutine builds its own while the registries holding them are
+// shared.
+
+const (
+	stressWorkers = 8
+	stressRounds  = 50
+)
+
+// stress runs fn on stressWorkers goroutines and waits for them
+func stress(t *testing.T, fn func(worker int)) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for w := range stressWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(w)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStressStyles(t *testing.T) {
+	withRenderCache(t, 64, 0)
+	shared := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	want := render(t, shared, "shared")
+
+	stress(t, func(w int) {
+		for i := range stressRounds {
+			id := StylePadding(shared, 0, cInt(i%3), 0, 0)
+			copied := CopyStyle(id)
+			text := cString(t, fmt.Sprintf("w%d-%d", w, i))
+			FreeString(StyleRender(copied, text))
+			FreeStyle(copied)
+			FreeStyle(id)
+
+			if got := render(t, shared, "shared"); got != want {
+				t.Errorf("a shared style rendered %q, want %q", got, want)
+				return
+			}
+			// Handles freed by another goroutine may be reused, but never
+			// resolve to the new style
+			if StyleGetBold(id) != 0 {
+				t.Error("a freed handle still resolves")
+				return
+			}
+		}
+	})
+}
+
+func TestStressTables(t *testing.T) {
+	header := cStrings(t, []string{"n", "square"})
+	stress(t, func(w int) {
+		for i := range stressRounds {
+			id := NewTable()
+			TableAddHeaders(id, header, 2)
+			row := cStrings(t, []string{fmt.Sprint(i), fmt.Sprint(i * i)})
+			TableAddRow(id, row, 2)
+			TableSetBorder(id, cInt(i%3))
+			FreeString(RenderTable(id))
+			FreeTable(id)
+		}
+	})
+}
+
+func TestStressTrees(t *testing.T) {
+	item := keep(t, StyleItalic(keep(t, NewStyle()), 1))
+	stress(t, func(w int) {
+		for i := range stressRounds {
+			root := newStressTree(t, w, i)
+			child := newStressTree(t, w, i+1)
+			TreeAddChildTree(root, child)
+			TreeSetItemStyle(root, item)
+			TreeSetEnumerator(root, cInt(i%3))
+			TreeReplaceChildValue(root, 0, cString(t, "replaced"))
+			FreeString(RenderTree(root))
+			FreeTree(child)
+			FreeTree(root)
+		}
+	})
+}
+
+func newStressTree(t *testing.T, w, i int) cHandle {
+	id := NewTree()
+	TreeSetRoot(id, cString(t, fmt.Sprintf("tree %d/%d", w, i)))
+	TreeAddChildValue(id, cString(t, "a"))
+	TreeAddChildValue(id, cString(t, "b"))
+	return id
+}
+
+func TestStressLists(t *testing.T) {
+	enum := keep(t, StyleFaint(keep(t, NewStyle()), 1))
+	items := []string{"one", "two", "three"}
+	stress(t, func(w int) {
+		for i := range stressRounds {
+			id := NewList()
+			sub := NewList()
+			for _, item := range items {
+				ListAddItem(id, cString(t, item))
+				ListAddItem(sub, cString(t, item))
+			}
+			ListAddSublist(id, sub)
+			ListSetEnumerator(id, listEnumCheckbox)
+			ListSetItemChecked(id, cInt(i%len(items)), 1)
+			ListSetEnumeratorStyle(id, enum)
+			FreeString(RenderList(id))
+			FreeList(sub)
+			FreeList(id)
+		}
+	})
+}
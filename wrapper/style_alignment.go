@@ -25,6 +25,7 @@ func validatePadding(value int, name string) error {
 
 //export StyleAlignHorizontal
 func StyleAlignHorizontal(id C.uint64_t, position C.double) C.uint64_t {
+	defer recoverPanic("StyleAlignHorizontal")
 	style, err := Style.SafeGet(uint64(id), "align-horizontal")
 	if err != nil {
 		Log(LogLevelError, "StyleAlignHorizontal style error: %v", err)
@@ -44,6 +45,7 @@ func StyleAlignHorizontal(id C.uint64_t, position C.double) C.uint64_t {
 
 //export StyleAlignVertical
 func StyleAlignVertical(id C.uint64_t, position C.double) C.uint64_t {
+	defer recoverPanic("StyleAlignVertical")
 	style, err := Style.SafeGet(uint64(id), "align-vertical")
 	if err != nil {
 		Log(LogLevelError, "StyleAlignVertical style error: %v", err)
@@ -63,6 +65,7 @@ func StyleAlignVertical(id C.uint64_t, position C.double) C.uint64_t {
 
 //export StylePadding
 func StylePadding(id C.uint64_t, top, right, bottom, left C.int) C.uint64_t {
+	defer recoverPanic("StylePadding")
 	style, err := Style.SafeGet(uint64(id), "padding")
 	if err != nil {
 		Log(LogLevelError, "StylePadding style error: %v", err)
@@ -93,6 +96,7 @@ func StylePadding(id C.uint64_t, top, right, bottom, left C.int) C.uint64_t {
 
 //export StylePaddingTop
 func StylePaddingTop(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StylePaddingTop")
 	style, err := Style.SafeGet(uint64(id), "padding-top")
 	if err != nil {
 		Log(LogLevelError, "StylePaddingTop style error: %v", err)
@@ -112,6 +116,7 @@ func StylePaddingTop(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StylePaddingRight
 func StylePaddingRight(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StylePaddingRight")
 	style, err := Style.SafeGet(uint64(id), "padding-right")
 	if err != nil {
 		Log(LogLevelError, "StylePaddingRight style error: %v", err)
@@ -131,6 +136,7 @@ func StylePaddingRight(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StylePaddingBottom
 func StylePaddingBottom(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StylePaddingBottom")
 	style, err := Style.SafeGet(uint64(id), "padding-bottom")
 	if err != nil {
 		Log(LogLevelError, "StylePaddingBottom style error: %v", err)
@@ -150,6 +156,7 @@ func StylePaddingBottom(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StylePaddingLeft
 func StylePaddingLeft(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StylePaddingLeft")
 	style, err := Style.SafeGet(uint64(id), "padding-left")
 	if err != nil {
 		Log(LogLevelError, "StylePaddingLeft style error: %v", err)
@@ -169,6 +176,7 @@ func StylePaddingLeft(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleMargin
 func StyleMargin(id C.uint64_t, top, right, bottom, left C.int) C.uint64_t {
+	defer recoverPanic("StyleMargin")
 	style, err := Style.SafeGet(uint64(id), "margin")
 	if err != nil {
 		Log(LogLevelError, "StyleMargin style error: %v", err)
@@ -199,6 +207,7 @@ func StyleMargin(id C.uint64_t, top, right, bottom, left C.int) C.uint64_t {
 
 //export StyleMarginTop
 func StyleMarginTop(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleMarginTop")
 	style, err := Style.SafeGet(uint64(id), "margin-top")
 	if err != nil {
 		Log(LogLevelError, "StyleMarginTop style error: %v", err)
@@ -218,6 +227,7 @@ func StyleMarginTop(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleMarginRight
 func StyleMarginRight(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleMarginRight")
 	style, err := Style.SafeGet(uint64(id), "margin-right")
 	if err != nil {
 		Log(LogLevelError, "StyleMarginRight style error: %v", err)
@@ -237,6 +247,7 @@ func StyleMarginRight(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleMarginBottom
 func StyleMarginBottom(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleMarginBottom")
 	style, err := Style.SafeGet(uint64(id), "margin-bottom")
 	if err != nil {
 		Log(LogLevelError, "StyleMarginBottom style error: %v", err)
@@ -256,6 +267,7 @@ func StyleMarginBottom(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleMarginLeft
 func StyleMarginLeft(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleMarginLeft")
 	style, err := Style.SafeGet(uint64(id), "margin-left")
 	if err != nil {
 		Log(LogLevelError, "StyleMarginLeft style error: %v", err)
@@ -12,6 +12,7 @@ import (
 
 //export StyleBorder
 func StyleBorder(id C.uint64_t, border C.CBorder) C.uint64_t {
+	defer recoverPanic("StyleBorder")
 	style, err := Style.SafeGet(uint64(id), "border")
 	if err != nil {
 		Log(LogLevelError, "StyleBorder style error: %v", err)
@@ -41,6 +42,7 @@ func StyleBorder(id C.uint64_t, border C.CBorder) C.uint64_t {
 
 //export StyleBorderStyle
 func StyleBorderStyle(id C.uint64_t, border C.CBorder) C.uint64_t {
+	defer recoverPanic("StyleBorderStyle")
 	style, err := Style.SafeGet(uint64(id), "border-style")
 	if err != nil {
 		Log(LogLevelError, "StyleBorderStyle style error: %v", err)
@@ -70,6 +72,7 @@ func StyleBorderStyle(id C.uint64_t, border C.CBorder) C.uint64_t {
 
 //export StyleBorderBackground
 func StyleBorderBackground(id C.uint64_t, color *C.char) C.uint64_t {
+	defer recoverPanic("StyleBorderBackground")
 	style, err := Style.SafeGet(uint64(id), "border-background")
 	if err != nil {
 		Log(LogLevelError, "StyleBorderBackground style error: %v", err)
@@ -90,6 +93,7 @@ func StyleBorderBackground(id C.uint64_t, color *C.char) C.uint64_t {
 
 //export StyleBorderForeground
 func StyleBorderForeground(id C.uint64_t, color *C.char) C.uint64_t {
+	defer recoverPanic("StyleBorderForeground")
 	style, err := Style.SafeGet(uint64(id), "border-foreground")
 	if err != nil {
 		Log(LogLevelError, "StyleBorderForeground style error: %v", err)
@@ -110,26 +114,12 @@ func StyleBorderForeground(id C.uint64_t, color *C.char) C.uint64_t {
 
 //export StyleGetBorderStyle
 func StyleGetBorderStyle(id C.uint64_t) C.CBorder {
+	defer recoverPanic("StyleGetBorderStyle")
 	style, err := Style.SafeGet(uint64(id), "get-border-style")
 	if err != nil {
 		Log(LogLevelError, "StyleGetBorderStyle error: %v", err)
 		return C.CBorder{}
 	}
 
-	border := style.GetBorderStyle()
-	return C.CBorder{
-		Top:          C.CString(border.Top),
-		Bottom:       C.CString(border.Bottom),
-		Left:         C.CString(border.Left),
-		Right:        C.CString(border.Right),
-		TopLeft:      C.CString(border.TopLeft),
-		TopRight:     C.CString(border.TopRight),
-		BottomLeft:   C.CString(border.BottomLeft),
-		BottomRight:  C.CString(border.BottomRight),
-		MiddleLeft:   C.CString(border.MiddleLeft),
-		MiddleRight:  C.CString(border.MiddleRight),
-		Middle:       C.CString(border.Middle),
-		MiddleTop:    C.CString(border.MiddleTop),
-		MiddleBottom: C.CString(border.MiddleBottom),
-	}
+	return toBorder(style.GetBorderStyle())
 }
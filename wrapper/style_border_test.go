@@ -0,0 +1,132 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// borderOf copies a C border the library still owns
+func borderOf(b cBorder) lipgloss.Border {
+	return lipgloss.Border{
+		Top:          peekString(b.Top),
+		Bottom:       peekString(b.Bottom),
+		Left:         peekString(b.Left),
+		Right:        peekString(b.Right),
+		TopLeft:      peekString(b.TopLeft),
+		TopRight:     peekString(b.TopRight),
+		BottomLeft:   peekString(b.BottomLeft),
+		BottomRight:  peekString(b.BottomRight),
+		MiddleLeft:   peekString(b.MiddleLeft),
+		MiddleRight:  peekString(b.MiddleRight),
+		Middle:       peekString(b.Middle),
+		MiddleTop:    peekString(b.MiddleTop),
+		MiddleBottom: peekString(b.MiddleBottom),
+	}
+}
+
+func TestPredefinedBorders(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		get  func() cBorder
+		want lipgloss.Border
+	}{
+		{"BlockBorder", BlockBorder, lipgloss.BlockBorder()},
+		{"DoubleBorder", DoubleBorder, lipgloss.DoubleBorder()},
+		{"HiddenBorder", HiddenBorder, lipgloss.HiddenBorder()},
+		{"InnerHalfBlockBorder", InnerHalfBlockBorder, lipgloss.InnerHalfBlockBorder()},
+		{"NormalBorder", NormalBorder, lipgloss.NormalBorder()},
+		{"OuterHalfBlockBorder", OuterHalfBlockBorder, lipgloss.OuterHalfBlockBorder()},
+		{"RoundedBorder", RoundedBorder, lipgloss.RoundedBorder()},
+		{"ThickBorder", ThickBorder, lipgloss.ThickBorder()},
+	} {
+		b := tc.get()
+		if got := borderOf(b); got != tc.want {
+			t.Errorf("%s() = %+v, want %+v", tc.name, got, tc.want)
+		}
+		FreeBorder(b)
+	}
+}
+
+func TestBorderSizes(t *testing.T) {
+	b := CreateCustomBorder(
+		cString(t, "═漢"), cString(t, "-"), cString(t, "漢"), cString(t, "]"),
+		cString(t, "+"), cString(t, "+"), cString(t, "+"), cString(t, "+"),
+		cString(t, "+"), cString(t, "+"), cString(t, "+"), cString(t, "+"), cString(t, "+"))
+	defer FreeBorder(b)
+
+	if got := peekString(b.Left); got != "漢" {
+		t.Errorf("CreateCustomBorder left = %q", got)
+	}
+	if got := GetTopSize(b); got != 2 {
+		t.Errorf("GetTopSize = %d, want 2", got)
+	}
+	if got := GetBottomSize(b); got != 1 {
+		t.Errorf("GetBottomSize = %d, want 1", got)
+	}
+	if got := GetLeftSize(b); got != 2 {
+		t.Errorf("GetLeftSize = %d, want 2", got)
+	}
+	if got := GetRightSize(b); got != 1 {
+		t.Errorf("GetRightSize = %d, want 1", got)
+	}
+
+	var empty cBorder
+	if got := GetTopSize(empty); got != 0 {
+		t.Errorf("GetTopSize of an empty border = %d, want 0", got)
+	}
+}
+
+func TestBorderSetters(t *testing.T) {
+	rounded := RoundedBorder()
+	defer FreeBorder(rounded)
+
+	s := lipgloss.NewStyle()
+	withBorder := func(t *testing.T, id cHandle) cHandle {
+		if id = StyleBorder(id, rounded); id == 0 {
+			return 0
+		}
+		return keep(t, id)
+	}
+	checkSetters(t, []styleSetter{
+		{"StyleBorder", func(_ *testing.T, id cHandle) cHandle { return StyleBorder(id, rounded) },
+			s.Border(lipgloss.RoundedBorder()), nil},
+		{"StyleBorderStyle", func(_ *testing.T, id cHandle) cHandle { return StyleBorderStyle(id, rounded) },
+			s.BorderStyle(lipgloss.RoundedBorder()), nil},
+		{"StyleBorderForeground", func(t *testing.T, id cHandle) cHandle {
+			if id = withBorder(t, id); id == 0 {
+				return 0
+			}
+			return StyleBorderForeground(id, cString(t, "#ff8800"))
+		}, s.Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("#ff8800")),
+			func(id cHandle) cHandle { return StyleBorderForeground(id, cString(t, "#ff88")) }},
+		{"StyleBorderBackground", func(t *testing.T, id cHandle) cHandle {
+			if id = withBorder(t, id); id == 0 {
+				return 0
+			}
+			return StyleBorderBackground(id, cString(t, "21"))
+		}, s.Border(lipgloss.RoundedBorder()).BorderBackground(lipgloss.Color("21")),
+			func(id cHandle) cHandle { return StyleBorderBackground(id, cString(t, "")) }},
+	})
+}
+
+func TestStyleGetBorderStyle(t *testing.T) {
+	thick := ThickBorder()
+	defer FreeBorder(thick)
+	id := keep(t, StyleBorderStyle(keep(t, NewStyle()), thick))
+
+	b := StyleGetBorderStyle(id)
+	if got := borderOf(b); got != lipgloss.ThickBorder() {
+		t.Errorf("StyleGetBorderStyle = %+v, want the thick border", got)
+	}
+	FreeBorder(b)
+
+	expectLog(t, "StyleGetBorderStyle", func() {
+		b = StyleGetBorderStyle(stale())
+	})
+	if b.Top != nil {
+		t.Error("StyleGetBorderStyle of a freed style returned strings")
+	}
+}
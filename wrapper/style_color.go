@@ -10,6 +10,7 @@ import "github.com/charmbracelet/lipgloss"
 
 //export StyleForeground
 func StyleForeground(id C.uint64_t, color *C.char) C.uint64_t {
+	defer recoverPanic("StyleForeground")
 	style, err := Style.SafeGet(uint64(id), "foreground")
 	if err != nil {
 		Log(LogLevelError, "StyleForeground style error: %v", err)
@@ -30,6 +31,7 @@ func StyleForeground(id C.uint64_t, color *C.char) C.uint64_t {
 
 //export StyleBackground
 func StyleBackground(id C.uint64_t, color *C.char) C.uint64_t {
+	defer recoverPanic("StyleBackground")
 	style, err := Style.SafeGet(uint64(id), "background")
 	if err != nil {
 		Log(LogLevelError, "StyleBackground style error: %v", err)
@@ -50,6 +52,7 @@ func StyleBackground(id C.uint64_t, color *C.char) C.uint64_t {
 
 //export StyleColorWhitespace
 func StyleColorWhitespace(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleColorWhitespace")
 	style, err := Style.SafeGet(uint64(id), "color-whitespace")
 	if err != nil {
 		Log(LogLevelError, "StyleColorWhitespace error: %v", err)
@@ -64,6 +67,7 @@ func StyleColorWhitespace(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleMarginBackground
 func StyleMarginBackground(id C.uint64_t, color *C.char) C.uint64_t {
+	defer recoverPanic("StyleMarginBackground")
 	style, err := Style.SafeGet(uint64(id), "margin-background")
 	if err != nil {
 		Log(LogLevelError, "StyleMarginBackground style error: %v", err)
@@ -0,0 +1,32 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestColorSetters(t *testing.T) {
+	s := lipgloss.NewStyle()
+	checkSetters(t, []styleSetter{
+		{"StyleForeground", func(t *testing.T, id cHandle) cHandle { return StyleForeground(id, cString(t, "#ff5f87")) },
+			s.Foreground(lipgloss.Color("#ff5f87")), func(id cHandle) cHandle { return StyleForeground(id, cString(t, "#ff5f8")) }},
+		{"StyleBackground", func(t *testing.T, id cHandle) cHandle { return StyleBackground(id, cString(t, "63")) },
+			s.Background(lipgloss.Color("63")), func(id cHandle) cHandle { return StyleBackground(id, cString(t, "")) }},
+		{"StyleMarginBackground", func(t *testing.T, id cHandle) cHandle {
+			if id = StyleMargin(id, 0, 1, 0, 1); id == 0 {
+				return 0
+			}
+			return StyleMarginBackground(keep(t, id), cString(t, "#333"))
+		}, s.Margin(0, 1, 0, 1).MarginBackground(lipgloss.Color("#333")),
+			func(id cHandle) cHandle { return StyleMarginBackground(id, nil) }},
+		{"StyleColorWhitespace", func(t *testing.T, id cHandle) cHandle {
+			if id = StyleBackground(id, cString(t, "63")); id == 0 {
+				return 0
+			}
+			return StyleColorWhitespace(keep(t, StyleWidth(keep(t, id), 8)), 0)
+		}, s.Background(lipgloss.Color("63")).Width(8).ColorWhitespace(false), nil},
+	})
+}
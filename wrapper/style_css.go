@@ -0,0 +1,410 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// CSSError reports a declaration StyleFromCSS could not apply
+type CSSError struct {
+	Property string
+	Message  string
+}
+
+func (e *CSSError) Error() string {
+	if e.Property == "" {
+		return fmt.Sprintf("css error: %s", e.Message)
+	}
+	return fmt.Sprintf("css error at %q: %s", e.Property, e.Message)
+}
+
+func cssErrorf(property, format string, args ...interface{}) error {
+	return &CSSError{Property: property, Message: fmt.Sprintf(format, args...)}
+}
+
+// cssNamedColors holds the CSS basic color keywords plus a few common
+// extended ones
+var cssNamedColors = map[string]string{
+	"black":   "#000000",
+	"silver":  "#C0C0C0",
+	"gray":    "#808080",
+	"grey":    "#808080",
+	"white":   "#FFFFFF",
+	"maroon":  "#800000",
+	"red":     "#FF0000",
+	"purple":  "#800080",
+	"fuchsia": "#FF00FF",
+	"magenta": "#FF00FF",
+	"green":   "#008000",
+	"lime":    "#00FF00",
+	"olive":   "#808000",
+	"yellow":  "#FFFF00",
+	"navy":    "#000080",
+	"blue":    "#0000FF",
+	"teal":    "#008080",
+	"aqua":    "#00FFFF",
+	"cyan":    "#00FFFF",
+	"orange":  "#FFA500",
+	"pink":    "#FFC0CB",
+	"brown":   "#A52A2A",
+	"gold":    "#FFD700",
+	"violet":  "#EE82EE",
+	"indigo":  "#4B0082",
+}
+
+var (
+	cssCommentRe = regexp.MustCompile(`(?s)/\*.*?\*/`)
+	cssRGBRe     = regexp.MustCompile(`^rgb\(\s*(\d{1,3})\s*,\s*(\d{1,3})\s*,\s*(\d{1,3})\s*\)$`)
+)
+
+// cssColor converts hex, rgb(), named and ANSI colors
+func cssColor(property, value string) (lipgloss.TerminalColor, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if hex, ok := cssNamedColors[value]; ok {
+		return lipgloss.Color(hex), nil
+	}
+	if m := cssRGBRe.FindStringSubmatch(value); m != nil {
+		var rgb [3]int
+		for i := range rgb {
+			rgb[i], _ = strconv.Atoi(m[i+1])
+			if rgb[i] > 255 {
+				return nil, cssErrorf(property, "rgb component %d out of range", rgb[i])
+			}
+		}
+		return lipgloss.Color(fmt.Sprintf("#%02X%02X%02X", rgb[0], rgb[1], rgb[2])), nil
+	}
+	if strings.HasPrefix(value, "#") {
+		if err := Validate.Color(value, "css"); err != nil {
+			return nil, cssErrorf(property, "invalid color %q", value)
+		}
+		if _, err := strconv.ParseUint(value[1:], 16, 32); err != nil {
+			return nil, cssErrorf(property, "invalid color %q", value)
+		}
+		return lipgloss.Color(value), nil
+	}
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(value), nil
+	}
+	return nil, cssErrorf(property, "unsupported color %q", value)
+}
+
+// cssLength reads a cell count, allowing an optional "ch" unit
+func cssLength(property, value string) (int, error) {
+	trimmed := strings.TrimSuffix(value, "ch")
+	n, err := strconv.Atoi(trimmed)
+	if err != nil {
+		digits := strings.TrimRight(trimmed, "abcdefghijklmnopqrstuvwxyz%")
+		if _, numErr := strconv.Atoi(digits); numErr == nil {
+			return 0, cssErrorf(property, "unsupported unit in %q (lengths are in terminal cells, use plain numbers or ch)", value)
+		}
+		return 0, cssErrorf(property, "invalid length %q", value)
+	}
+	if err := Validate.Dimension(n, property); err != nil {
+		return 0, cssErrorf(property, "%v", err)
+	}
+	return n, nil
+}
+
+// cssSides reads the 1 to 4 value shorthand used by padding and margin
+func cssSides(property, value string) ([]int, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields) > 4 {
+		return nil, cssErrorf(property, "expected 1 to 4 values, got %d", len(fields))
+	}
+	sides := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := cssLength(property, f)
+		if err != nil {
+			return nil, err
+		}
+		sides[i] = n
+	}
+	return sides, nil
+}
+
+// cssBorders maps border-style keywords to lipgloss borders. CSS keywords
+// without a terminal equivalent fall back to the closest one.
+var cssBorders = map[string]func() lipgloss.Border{
+	"solid":   lipgloss.NormalBorder,
+	"normal":  lipgloss.NormalBorder,
+	"rounded": lipgloss.RoundedBorder,
+	"double":  lipgloss.DoubleBorder,
+	"block":   lipgloss.BlockBorder,
+	"hidden":  lipgloss.HiddenBorder,
+	"dashed":  lipgloss.NormalBorder,
+	"dotted":  lipgloss.NormalBorder,
+}
+
+// cssBorderWidth reports whether token is a border width: thin, medium,
+// thick or a px length. Terminal borders are always one cell wide, so
+// widths are accepted and ignored.
+func cssBorderWidth(property, token string) bool {
+	switch token {
+	case "thin", "medium", "thick":
+		return true
+	}
+	if n, ok := strings.CutSuffix(token, "px"); ok {
+		_, err := cssLength(property, n)
+		return err == nil
+	}
+	return false
+}
+
+// cssBorderShorthand reads "[<width>] <style> [<color>]", in any order. A
+// bare number is an ANSI color, not a width.
+func cssBorderShorthand(property, value string) (lipgloss.Border, lipgloss.TerminalColor, error) {
+	var border lipgloss.Border
+	var color lipgloss.TerminalColor
+	found := false
+	for _, token := range strings.Fields(value) {
+		if b, ok := cssBorders[token]; ok {
+			border, found = b(), true
+			continue
+		}
+		if cssBorderWidth(property, token) {
+			continue
+		}
+		c, err := cssColor(property, token)
+		if err != nil {
+			return border, nil, cssErrorf(property, "unsupported value %q", token)
+		}
+		color = c
+	}
+	if !found {
+		return border, nil, cssErrorf(property, "missing border style (one of solid, rounded, double, block, hidden)")
+	}
+	return border, color, nil
+}
+
+// cssBorder applies "border" and "border-style" to every side
+func cssBorder(s lipgloss.Style, property, value string) (lipgloss.Style, error) {
+	if value == "none" || value == "0" {
+		return s.UnsetBorderStyle().BorderTop(false).BorderRight(false).BorderBottom(false).BorderLeft(false), nil
+	}
+	border, color, err := cssBorderShorthand(property, value)
+	if err != nil {
+		return s, err
+	}
+	s = s.Border(border)
+	if color != nil {
+		s = s.BorderForeground(color)
+	}
+	return s, nil
+}
+
+// cssBorderSides holds the setters for each "border-<side>" shorthand
+var cssBorderSides = map[string]struct {
+	on    func(lipgloss.Style, bool) lipgloss.Style
+	color func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style
+}{
+	"border-top":    {lipgloss.Style.BorderTop, lipgloss.Style.BorderTopForeground},
+	"border-right":  {lipgloss.Style.BorderRight, lipgloss.Style.BorderRightForeground},
+	"border-bottom": {lipgloss.Style.BorderBottom, lipgloss.Style.BorderBottomForeground},
+	"border-left":   {lipgloss.Style.BorderLeft, lipgloss.Style.BorderLeftForeground},
+}
+
+// cssBorderSide applies "border-top: [<width>] <style> [<color>]" and the
+// other sides. A terminal border has one style for all sides, so the style
+// given for a side replaces it for the whole border.
+func cssBorderSide(s lipgloss.Style, property, value string) (lipgloss.Style, error) {
+	side := cssBorderSides[property]
+	if value == "none" || value == "0" {
+		return side.on(s, false), nil
+	}
+	border, color, err := cssBorderShorthand(property, value)
+	if err != nil {
+		return s, err
+	}
+	s = side.on(s.BorderStyle(border), true)
+	if color != nil {
+		s = side.color(s, color)
+	}
+	return s, nil
+}
+
+// applyCSSDeclaration applies one "property: value" declaration
+func applyCSSDeclaration(s lipgloss.Style, property, value string) (lipgloss.Style, error) {
+	lower := strings.ToLower(value)
+	switch property {
+	case "color":
+		c, err := cssColor(property, value)
+		return s.Foreground(c), err
+	case "background", "background-color":
+		c, err := cssColor(property, value)
+		return s.Background(c), err
+
+	case "font-weight":
+		switch lower {
+		case "bold", "bolder":
+			return s.Bold(true), nil
+		case "normal":
+			return s.Bold(false).Faint(false), nil
+		case "lighter":
+			return s.Faint(true), nil
+		}
+		n, err := strconv.Atoi(lower)
+		if err != nil || n < 1 || n > 1000 {
+			return s, cssErrorf(property, "unsupported value %q", value)
+		}
+		return s.Bold(n >= 600).Faint(n < 400), nil
+	case "font-style":
+		switch lower {
+		case "italic", "oblique":
+			return s.Italic(true), nil
+		case "normal":
+			return s.Italic(false), nil
+		}
+		return s, cssErrorf(property, "unsupported value %q", value)
+	case "text-decoration", "text-decoration-line":
+		s = s.Underline(false).Strikethrough(false).Blink(false)
+		for _, token := range strings.Fields(lower) {
+			switch token {
+			case "underline":
+				s = s.Underline(true)
+			case "line-through":
+				s = s.Strikethrough(true)
+			case "blink":
+				s = s.Blink(true)
+			case "none":
+			default:
+				return s, cssErrorf(property, "unsupported value %q", token)
+			}
+		}
+		return s, nil
+
+	case "text-align":
+		pos, ok := map[string]lipgloss.Position{
+			"left": lipgloss.Left, "start": lipgloss.Left, "center": lipgloss.Center,
+			"right": lipgloss.Right, "end": lipgloss.Right,
+		}[lower]
+		if !ok {
+			return s, cssErrorf(property, "unsupported value %q", value)
+		}
+		return s.AlignHorizontal(pos), nil
+	case "vertical-align":
+		pos, ok := map[string]lipgloss.Position{
+			"top": lipgloss.Top, "middle": lipgloss.Center, "bottom": lipgloss.Bottom,
+		}[lower]
+		if !ok {
+			return s, cssErrorf(property, "unsupported value %q", value)
+		}
+		return s.AlignVertical(pos), nil
+
+	case "padding", "margin":
+		sides, err := cssSides(property, lower)
+		if err != nil {
+			return s, err
+		}
+		if property == "padding" {
+			return s.Padding(sides...), nil
+		}
+		return s.Margin(sides...), nil
+
+	case "border", "border-style":
+		return cssBorder(s, property, lower)
+	case "border-color":
+		fields := strings.Fields(value)
+		if len(fields) == 0 || len(fields) > 4 {
+			return s, cssErrorf(property, "expected 1 to 4 colors, got %d", len(fields))
+		}
+		colors := make([]lipgloss.TerminalColor, len(fields))
+		for i, f := range fields {
+			c, err := cssColor(property, f)
+			if err != nil {
+				return s, err
+			}
+			colors[i] = c
+		}
+		return s.BorderForeground(colors...), nil
+	case "border-top", "border-right", "border-bottom", "border-left":
+		return cssBorderSide(s, property, lower)
+
+	case "display":
+		switch lower {
+		case "inline":
+			return s.Inline(true), nil
+		case "block":
+			return s.Inline(false), nil
+		}
+		return s, cssErrorf(property, "unsupported value %q", value)
+	case "tab-size":
+		n, err := cssLength(property, lower)
+		return s.TabWidth(n), err
+	}
+
+	lengths := map[string]func(lipgloss.Style, int) lipgloss.Style{
+		"width":          lipgloss.Style.Width,
+		"height":         lipgloss.Style.Height,
+		"max-width":      lipgloss.Style.MaxWidth,
+		"max-height":     lipgloss.Style.MaxHeight,
+		"padding-top":    lipgloss.Style.PaddingTop,
+		"padding-right":  lipgloss.Style.PaddingRight,
+		"padding-bottom": lipgloss.Style.PaddingBottom,
+		"padding-left":   lipgloss.Style.PaddingLeft,
+		"margin-top":     lipgloss.Style.MarginTop,
+		"margin-right":   lipgloss.Style.MarginRight,
+		"margin-bottom":  lipgloss.Style.MarginBottom,
+		"margin-left":    lipgloss.Style.MarginLeft,
+	}
+	if set, ok := lengths[property]; ok {
+		n, err := cssLength(property, lower)
+		return set(s, n), err
+	}
+	return s, cssErrorf(property, "unsupported property")
+}
+
+// styleFromCSS builds a style from a list of CSS declarations
+func styleFromCSS(css string) (lipgloss.Style, error) {
+	s := lipgloss.NewStyle()
+	css = cssCommentRe.ReplaceAllString(css, "")
+	for _, decl := range strings.Split(css, ";") {
+		if strings.TrimSpace(decl) == "" {
+			continue
+		}
+		property, value, ok := strings.Cut(decl, ":")
+		property = strings.ToLower(strings.TrimSpace(property))
+		if !ok || property == "" {
+			return s, cssErrorf("", "malformed declaration %q (expected property: value)", strings.TrimSpace(decl))
+		}
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		if value == "" {
+			return s, cssErrorf(property, "missing value")
+		}
+
+		var err error
+		if s, err = applyCSSDeclaration(s, property, value); err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+// StyleFromCSS registers a style described by CSS declarations such as
+// "color: #ff0; padding: 1 2; border: rounded". It returns 0 on failure and,
+// when errOut is not NULL, a description of the offending declaration that
+// must be released with FreeString.
+//
+//export StyleFromCSS
+func StyleFromCSS(css *C.char, errOut **C.char) C.uint64_t {
+	defer recoverPanic("StyleFromCSS")
+	if errOut != nil {
+		*errOut = nil
+	}
+	style, err := styleFromCSS(String.GoString(css))
+	if err != nil {
+		Log(LogLevelError, "StyleFromCSS: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	return C.uint64_t(styleReg.Register(&style))
+}
@@ -0,0 +1,72 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestStyleFromCSS(t *testing.T) {
+	var errOut *cChar
+	id := keep(t, StyleFromCSS(cString(t, "color: #ff0; padding: 1 2; font-weight: bold; border: rounded"), &errOut))
+	if errOut != nil {
+		t.Errorf("StyleFromCSS set an error on success: %s", takeString(errOut))
+	}
+	want := lipgloss.NewStyle().Foreground(lipgloss.Color("#ff0")).Padding(1, 2).Bold(true).Border(lipgloss.RoundedBorder())
+	if got := render(t, id, "css"); got != want.Render("css") {
+		t.Errorf("StyleFromCSS renders %q, want %q", got, want.Render("css"))
+	}
+
+	expectLog(t, "StyleFromCSS", func() {
+		if got := StyleFromCSS(cString(t, "padding: 1 nope"), &errOut); got != 0 {
+			t.Errorf("StyleFromCSS with an invalid value = %#x", got)
+		}
+	})
+	if msg := takeString(errOut); !strings.Contains(msg, "padding") {
+		t.Errorf("StyleFromCSS error = %q, want it to name the declaration", msg)
+	}
+	expectLog(t, "StyleFromCSS", func() { StyleFromCSS(cString(t, "no-colon"), nil) })
+}
+
+func TestCSSBorder(t *testing.T) {
+	base := lipgloss.NewStyle()
+	red := lipgloss.Color("#f00")
+	for _, c := range []struct {
+		css  string
+		want lipgloss.Style
+	}{
+		{"border: rounded 212", base.Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("212"))},
+		{"border: 1px solid red", base.Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("#FF0000"))},
+		{"border: thin double", base.Border(lipgloss.DoubleBorder())},
+		// thick is a width, not the lipgloss thick border
+		{"border: thick medium solid", base.Border(lipgloss.NormalBorder())},
+		{"border-left: 1px solid #f00", base.BorderStyle(lipgloss.NormalBorder()).BorderLeft(true).BorderLeftForeground(red)},
+		{"border: rounded; border-left: none", base.Border(lipgloss.RoundedBorder()).BorderLeft(false)},
+		{"border: rounded; border-top: double #f00", base.Border(lipgloss.DoubleBorder()).BorderTopForeground(red)},
+	} {
+		got := base
+		for _, decl := range strings.Split(c.css, ";") {
+			property, value, _ := strings.Cut(decl, ":")
+			var err error
+			if got, err = applyCSSDeclaration(got, strings.TrimSpace(property), strings.TrimSpace(value)); err != nil {
+				t.Errorf("%s failed: %v", c.css, err)
+			}
+		}
+		if got.Render("x") != c.want.Render("x") {
+			t.Errorf("%s renders %q, want %q", c.css, got.Render("x"), c.want.Render("x"))
+		}
+	}
+
+	for _, c := range []struct{ property, value string }{
+		{"border", "rounded 2em"},
+		{"border", "thick"},
+		{"border-left", "#f00"},
+	} {
+		if _, err := applyCSSDeclaration(base, c.property, c.value); err == nil {
+			t.Errorf("%s: %s was accepted", c.property, c.value)
+		}
+	}
+}
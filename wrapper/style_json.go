@@ -0,0 +1,400 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// jsonField is a key/value pair of a jsonObject
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+// jsonObject is a JSON object that keeps its keys in insertion order
+type jsonObject []jsonField
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, f := range o {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(f.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(f.value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// isSet reports whether the property read by get and written by set is set
+// on s rather than left at its default. Inherit only copies properties s
+// does not set, so inheriting a probe that holds a different value leaves a
+// set property alone. Padding and margins are not inherited and cannot be
+// told apart from their defaults this way.
+func isSet[V comparable](s lipgloss.Style, get func(lipgloss.Style) V, set func(lipgloss.Style, V) lipgloss.Style, probes [2]V) bool {
+	probe := probes[0]
+	if get(s) == probe {
+		probe = probes[1]
+	}
+	return get(s.Inherit(set(lipgloss.NewStyle(), probe))) != probe
+}
+
+// Two distinct values of each property type, for isSet
+var (
+	boolProbes     = [2]bool{true, false}
+	intProbes      = [2]int{1, 2}
+	colorProbes    = [2]lipgloss.TerminalColor{lipgloss.Color("1"), lipgloss.Color("2")}
+	positionProbes = [2]lipgloss.Position{lipgloss.Left, lipgloss.Right}
+)
+
+// marginBackground reads the margin background color, which lipgloss
+// v1.0.0 has no getter for, from the style's private field. ok is false if
+// a lipgloss update renamed or retyped that field.
+func marginBackground(s lipgloss.Style) (c lipgloss.TerminalColor, ok bool) {
+	field := reflect.ValueOf(&s).Elem().FieldByName("marginBgColor")
+	if !field.IsValid() || field.Type() != reflect.TypeOf((*lipgloss.TerminalColor)(nil)).Elem() {
+		return nil, false
+	}
+	c, _ = reflect.NewAt(field.Type(), unsafe.Pointer(field.UnsafeAddr())).Elem().Interface().(lipgloss.TerminalColor)
+	return c, true
+}
+
+// marginBackgroundSet reports whether s sets a margin background, using
+// the same probe as isSet
+func marginBackgroundSet(s lipgloss.Style) (lipgloss.TerminalColor, bool, error) {
+	c, ok := marginBackground(s)
+	if !ok {
+		return nil, false, errors.New("cannot read the margin background of this lipgloss version")
+	}
+	probe := colorProbes[0]
+	if c == probe {
+		probe = colorProbes[1]
+	}
+	inherited, _ := marginBackground(s.Inherit(lipgloss.NewStyle().MarginBackground(probe)))
+	return c, inherited != probe, nil
+}
+
+// colorJSON converts a color to the representation read by themeColor
+func colorJSON(c lipgloss.TerminalColor) (interface{}, bool) {
+	complete := func(c lipgloss.CompleteColor) jsonObject {
+		var o jsonObject
+		if c.TrueColor != "" {
+			o = append(o, jsonField{"true_color", c.TrueColor})
+		}
+		if c.ANSI256 != "" {
+			o = append(o, jsonField{"ansi256", c.ANSI256})
+		}
+		if c.ANSI != "" {
+			o = append(o, jsonField{"ansi", c.ANSI})
+		}
+		return o
+	}
+
+	switch c := c.(type) {
+	case lipgloss.Color:
+		return string(c), true
+	case lipgloss.ANSIColor:
+		return int(c), true
+	case lipgloss.AdaptiveColor:
+		return jsonObject{{"light", c.Light}, {"dark", c.Dark}}, true
+	case lipgloss.CompleteColor:
+		return complete(c), true
+	case lipgloss.CompleteAdaptiveColor:
+		return jsonObject{{"light", complete(c.Light)}, {"dark", complete(c.Dark)}}, true
+	}
+	return nil, false
+}
+
+func positionJSON(pos lipgloss.Position, names map[string]lipgloss.Position) interface{} {
+	keys := make([]string, 0, len(names))
+	for name := range names {
+		keys = append(keys, name)
+	}
+	sort.Strings(keys)
+	for _, name := range keys {
+		if names[name] == pos {
+			return name
+		}
+	}
+	return float64(pos)
+}
+
+func borderJSON(b lipgloss.Border) interface{} {
+	names := make([]string, 0, len(themeBorders))
+	for name := range themeBorders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if themeBorders[name]() == b {
+			return name
+		}
+	}
+
+	var o jsonObject
+	for _, c := range themeBorderChars {
+		if part := *c.part(&b); part != "" {
+			o = append(o, jsonField{c.name, part})
+		}
+	}
+	return o
+}
+
+// sidesJSON emits a four-sided property as one shorthand array when any
+// side is non-zero. Padding and margins are not inherited, so a side set to
+// 0 behaves exactly like an unset one.
+func sidesJSON(o jsonObject, shorthand string, top, right, bottom, left int) jsonObject {
+	if top == 0 && right == 0 && bottom == 0 && left == 0 {
+		return o
+	}
+	return append(o, jsonField{shorthand, []int{top, right, bottom, left}})
+}
+
+// borderColorsJSON emits per-side border colors, collapsing them into the
+// shorthand key when all four sides share one color
+func borderColorsJSON(o jsonObject, s lipgloss.Style, shorthand string, sides [4]struct {
+	key string
+	get func(lipgloss.Style) lipgloss.TerminalColor
+	set func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style
+}) jsonObject {
+	same := true
+	for _, side := range sides {
+		same = same && isSet(s, side.get, side.set, colorProbes) && side.get(s) == sides[0].get(s)
+	}
+	if same {
+		if c, ok := colorJSON(sides[0].get(s)); ok {
+			return append(o, jsonField{shorthand, c})
+		}
+	}
+	for _, side := range sides {
+		if !isSet(s, side.get, side.set, colorProbes) {
+			continue
+		}
+		if c, ok := colorJSON(side.get(s)); ok {
+			o = append(o, jsonField{side.key, c})
+		}
+	}
+	return o
+}
+
+// styleJSON lists every set property of s using the theme file schema. The
+// transform function is not serializable and is left out.
+func styleJSON(s lipgloss.Style) (jsonObject, error) {
+	var o jsonObject
+
+	colors := []struct {
+		key string
+		get func(lipgloss.Style) lipgloss.TerminalColor
+		set func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style
+	}{
+		{"foreground", lipgloss.Style.GetForeground, lipgloss.Style.Foreground},
+		{"background", lipgloss.Style.GetBackground, lipgloss.Style.Background},
+	}
+	for _, c := range colors {
+		if !isSet(s, c.get, c.set, colorProbes) {
+			continue
+		}
+		if v, ok := colorJSON(c.get(s)); ok {
+			o = append(o, jsonField{c.key, v})
+		}
+	}
+
+	bools := []struct {
+		key string
+		get func(lipgloss.Style) bool
+		set func(lipgloss.Style, bool) lipgloss.Style
+	}{
+		{"bold", lipgloss.Style.GetBold, lipgloss.Style.Bold},
+		{"italic", lipgloss.Style.GetItalic, lipgloss.Style.Italic},
+		{"underline", lipgloss.Style.GetUnderline, lipgloss.Style.Underline},
+		{"strikethrough", lipgloss.Style.GetStrikethrough, lipgloss.Style.Strikethrough},
+		{"reverse", lipgloss.Style.GetReverse, lipgloss.Style.Reverse},
+		{"blink", lipgloss.Style.GetBlink, lipgloss.Style.Blink},
+		{"faint", lipgloss.Style.GetFaint, lipgloss.Style.Faint},
+		{"underline_spaces", lipgloss.Style.GetUnderlineSpaces, lipgloss.Style.UnderlineSpaces},
+		{"strikethrough_spaces", lipgloss.Style.GetStrikethroughSpaces, lipgloss.Style.StrikethroughSpaces},
+		{"color_whitespace", lipgloss.Style.GetColorWhitespace, lipgloss.Style.ColorWhitespace},
+		{"inline", lipgloss.Style.GetInline, lipgloss.Style.Inline},
+	}
+	for _, b := range bools {
+		if isSet(s, b.get, b.set, boolProbes) {
+			o = append(o, jsonField{b.key, b.get(s)})
+		}
+	}
+
+	ints := []struct {
+		key string
+		get func(lipgloss.Style) int
+		set func(lipgloss.Style, int) lipgloss.Style
+	}{
+		{"width", lipgloss.Style.GetWidth, lipgloss.Style.Width},
+		{"height", lipgloss.Style.GetHeight, lipgloss.Style.Height},
+		{"max_width", lipgloss.Style.GetMaxWidth, lipgloss.Style.MaxWidth},
+		{"max_height", lipgloss.Style.GetMaxHeight, lipgloss.Style.MaxHeight},
+		{"tab_width", lipgloss.Style.GetTabWidth, lipgloss.Style.TabWidth},
+	}
+	for _, n := range ints {
+		if isSet(s, n.get, n.set, intProbes) {
+			o = append(o, jsonField{n.key, n.get(s)})
+		}
+	}
+
+	if isSet(s, lipgloss.Style.GetAlignHorizontal, lipgloss.Style.AlignHorizontal, positionProbes) {
+		o = append(o, jsonField{"align", positionJSON(s.GetAlignHorizontal(), themeHorizontal)})
+	}
+	if isSet(s, lipgloss.Style.GetAlignVertical, lipgloss.Style.AlignVertical, positionProbes) {
+		o = append(o, jsonField{"align_vertical", positionJSON(s.GetAlignVertical(), themeVertical)})
+	}
+
+	o = sidesJSON(o, "padding", s.GetPaddingTop(), s.GetPaddingRight(), s.GetPaddingBottom(), s.GetPaddingLeft())
+	o = sidesJSON(o, "margin", s.GetMarginTop(), s.GetMarginRight(), s.GetMarginBottom(), s.GetMarginLeft())
+	marginBg, set, err := marginBackgroundSet(s)
+	if err != nil {
+		return nil, err
+	}
+	if set {
+		if v, ok := colorJSON(marginBg); ok {
+			o = append(o, jsonField{"margin_background", v})
+		}
+	}
+
+	var border jsonObject
+	if isSet(s, lipgloss.Style.GetBorderStyle, lipgloss.Style.BorderStyle, [2]lipgloss.Border{lipgloss.NormalBorder(), lipgloss.HiddenBorder()}) {
+		border = append(border, jsonField{"style", borderJSON(s.GetBorderStyle())})
+	}
+	sides := []struct {
+		key string
+		get func(lipgloss.Style) bool
+		set func(lipgloss.Style, bool) lipgloss.Style
+	}{
+		{"top", lipgloss.Style.GetBorderTop, lipgloss.Style.BorderTop},
+		{"right", lipgloss.Style.GetBorderRight, lipgloss.Style.BorderRight},
+		{"bottom", lipgloss.Style.GetBorderBottom, lipgloss.Style.BorderBottom},
+		{"left", lipgloss.Style.GetBorderLeft, lipgloss.Style.BorderLeft},
+	}
+	for _, side := range sides {
+		if isSet(s, side.get, side.set, boolProbes) {
+			border = append(border, jsonField{side.key, side.get(s)})
+		}
+	}
+	if len(border) > 0 {
+		o = append(o, jsonField{"border", border})
+	}
+
+	o = borderColorsJSON(o, s, "border_foreground", [4]struct {
+		key string
+		get func(lipgloss.Style) lipgloss.TerminalColor
+		set func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style
+	}{
+		{"border_top_foreground", lipgloss.Style.GetBorderTopForeground, lipgloss.Style.BorderTopForeground},
+		{"border_right_foreground", lipgloss.Style.GetBorderRightForeground, lipgloss.Style.BorderRightForeground},
+		{"border_bottom_foreground", lipgloss.Style.GetBorderBottomForeground, lipgloss.Style.BorderBottomForeground},
+		{"border_left_foreground", lipgloss.Style.GetBorderLeftForeground, lipgloss.Style.BorderLeftForeground},
+	})
+	o = borderColorsJSON(o, s, "border_background", [4]struct {
+		key string
+		get func(lipgloss.Style) lipgloss.TerminalColor
+		set func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style
+	}{
+		{"border_top_background", lipgloss.Style.GetBorderTopBackground, lipgloss.Style.BorderTopBackground},
+		{"border_right_background", lipgloss.Style.GetBorderRightBackground, lipgloss.Style.BorderRightBackground},
+		{"border_bottom_background", lipgloss.Style.GetBorderBottomBackground, lipgloss.Style.BorderBottomBackground},
+		{"border_left_background", lipgloss.Style.GetBorderLeftBackground, lipgloss.Style.BorderLeftBackground},
+	})
+
+	if s.Value() != "" {
+		o = append(o, jsonField{"value", s.Value()})
+	}
+	return o, nil
+}
+
+// StyleToJSON serializes every property set on a style, using the same
+// schema as a style entry in a theme file. Properties left at their
+// defaults are omitted.
+//
+//export StyleToJSON
+func StyleToJSON(id C.uint64_t) (ret *C.char) {
+	defer recoverString("StyleToJSON", &ret, "")
+	style, err := Style.SafeGet(uint64(id), "to-json")
+	if err != nil {
+		Log(LogLevelError, "StyleToJSON style error: %v", err)
+		return Memory.CString("", "StyleToJSON result")
+	}
+
+	o, err := styleJSON(*style)
+	if err != nil {
+		Log(LogLevelError, "StyleToJSON error: %v", err)
+		return Memory.CString("", "StyleToJSON result")
+	}
+	data, err := json.Marshal(o)
+	if err != nil {
+		Log(LogLevelError, "StyleToJSON encoding error: %v", err)
+		return Memory.CString("", "StyleToJSON result")
+	}
+
+	cs, err := String.CString(string(data))
+	if err != nil {
+		Log(LogLevelError, "StyleToJSON memory allocation error: %v", err)
+		return Memory.CString("", "StyleToJSON result")
+	}
+	Memory.Track(unsafe.Pointer(cs), "StyleToJSON result")
+	return cs
+}
+
+// StyleFromJSON registers a style from JSON produced by StyleToJSON or
+// written by hand using the theme file schema. It returns 0 on failure and
+// reports errors through errOut as LoadTheme does.
+//
+//export StyleFromJSON
+func StyleFromJSON(data *C.char, errOut **C.char) C.uint64_t {
+	defer recoverPanic("StyleFromJSON")
+	if errOut != nil {
+		*errOut = nil
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal([]byte(String.GoString(data)), &doc); err != nil {
+		err = themeErrorf("", "%v", err)
+		Log(LogLevelError, "StyleFromJSON: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	entry, ok := themeMap(doc)
+	if !ok {
+		err := themeErrorf("", "expected object, got %s", describeThemeValue(doc))
+		Log(LogLevelError, "StyleFromJSON: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+
+	style, err := applyThemeEntry(lipgloss.NewStyle(), entry, "")
+	if err != nil {
+		Log(LogLevelError, "StyleFromJSON: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	return C.uint64_t(styleReg.Register(&style))
+}
@@ -0,0 +1,104 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestStyleJSONRoundTrip(t *testing.T) {
+	rounded := RoundedBorder()
+	defer FreeBorder(rounded)
+	id := keep(t, StyleItalic(keep(t, NewStyle()), 1))
+	id = keep(t, StyleMargin(id, 1, 0, 1, 0))
+	id = keep(t, StyleBorder(id, rounded))
+	id = keep(t, StyleForeground(id, cString(t, "212")))
+
+	data := takeString(StyleToJSON(id))
+	golden(t, "style_json", data)
+
+	var errOut *cChar
+	restored := keep(t, StyleFromJSON(cString(t, data), &errOut))
+	if got, want := render(t, restored, "json"), render(t, id, "json"); got != want {
+		t.Errorf("the restored style renders %q, want %q", got, want)
+	}
+	if got := takeString(StyleToJSON(keep(t, NewStyle()))); got != "{}" {
+		t.Errorf("StyleToJSON of an empty style = %q, want {}", got)
+	}
+}
+
+func TestStyleJSONSetProperties(t *testing.T) {
+	// Properties set to their default values are still serialized
+	id := keep(t, StyleBold(keep(t, NewStyle()), 0))
+	id = keep(t, StyleTabWidth(id, 4))
+	id = keep(t, StyleAlignHorizontal(id, 0))
+	if got, want := takeString(StyleToJSON(id)), `{"bold":false,"tab_width":4,"align":"left"}`; got != want {
+		t.Errorf("StyleToJSON = %s, want %s", got, want)
+	}
+
+	// An unset margin background keeps its color in lipgloss, but is unset
+	bg := keep(t, StyleMarginBackground(keep(t, NewStyle()), cString(t, "63")))
+	if got, want := takeString(StyleToJSON(bg)), `{"margin_background":"63"}`; got != want {
+		t.Errorf("StyleToJSON with a margin background = %s, want %s", got, want)
+	}
+	unset := lipgloss.Style.UnsetMarginBackground(*styleReg.Get(uint64(bg)))
+	if o, err := styleJSON(unset); err != nil || len(o) != 0 {
+		t.Errorf("styleJSON of an unset margin background = %v, %v", o, err)
+	}
+
+	if _, ok := marginBackground(lipgloss.NewStyle()); !ok {
+		t.Error("the margin background of this lipgloss version cannot be read")
+	}
+}
+
+func TestStyleJSONTabWidth(t *testing.T) {
+	for _, width := range []cInt{-1, 0, 8} {
+		id := keep(t, StyleTabWidth(keep(t, NewStyle()), width))
+		data := takeString(StyleToJSON(id))
+		var errOut *cChar
+		restored := StyleFromJSON(cString(t, data), &errOut)
+		if restored == 0 {
+			t.Errorf("StyleFromJSON(%s) failed: %s", data, takeString(errOut))
+			continue
+		}
+		keep(t, restored)
+		if got := StyleGetTabWidth(restored); got != width {
+			t.Errorf("tab width %d was restored from %s as %d", width, data, got)
+		}
+	}
+
+	expectLog(t, "StyleFromJSON", func() {
+		if got := StyleFromJSON(cString(t, `{"tab_width": -2}`), nil); got != 0 {
+			FreeStyle(got)
+			t.Error("StyleFromJSON accepted a tab width of -2")
+		}
+	})
+}
+
+func TestStyleJSONErrors(t *testing.T) {
+	expectLog(t, "StyleToJSON style error", func() {
+		if got := takeString(StyleToJSON(stale())); got != "" {
+			t.Errorf("StyleToJSON of a freed style = %q", got)
+		}
+	})
+
+	for _, tc := range []struct{ data, want string }{
+		{`{"bold": "yes"}`, "bold"},
+		{`[1, 2]`, "expected object"},
+		{`{`, "unexpected end"},
+	} {
+		var errOut *cChar
+		expectLog(t, "StyleFromJSON", func() {
+			if got := StyleFromJSON(cString(t, tc.data), &errOut); got != 0 {
+				FreeStyle(got)
+				t.Errorf("StyleFromJSON(%s) succeeded", tc.data)
+			}
+		})
+		if msg := takeString(errOut); !strings.Contains(msg, tc.want) {
+			t.Errorf("StyleFromJSON(%s) error = %q, want it to mention %q", tc.data, msg, tc.want)
+		}
+	}
+}
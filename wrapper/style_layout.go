@@ -21,6 +21,7 @@ func validateDimension(value int, name string) error {
 
 //export StyleWidth
 func StyleWidth(id C.uint64_t, width C.int) C.uint64_t {
+	defer recoverPanic("StyleWidth")
 	style, err := Style.SafeGet(uint64(id), "width")
 	if err != nil {
 		Log(LogLevelError, "StyleWidth style error: %v", err)
@@ -40,6 +41,7 @@ func StyleWidth(id C.uint64_t, width C.int) C.uint64_t {
 
 //export StyleHeight
 func StyleHeight(id C.uint64_t, height C.int) C.uint64_t {
+	defer recoverPanic("StyleHeight")
 	style, err := Style.SafeGet(uint64(id), "height")
 	if err != nil {
 		Log(LogLevelError, "StyleHeight style error: %v", err)
@@ -59,6 +61,7 @@ func StyleHeight(id C.uint64_t, height C.int) C.uint64_t {
 
 //export StyleMaxWidth
 func StyleMaxWidth(id C.uint64_t, width C.int) C.uint64_t {
+	defer recoverPanic("StyleMaxWidth")
 	style, err := Style.SafeGet(uint64(id), "max-width")
 	if err != nil {
 		Log(LogLevelError, "StyleMaxWidth style error: %v", err)
@@ -78,6 +81,7 @@ func StyleMaxWidth(id C.uint64_t, width C.int) C.uint64_t {
 
 //export StyleMaxHeight
 func StyleMaxHeight(id C.uint64_t, height C.int) C.uint64_t {
+	defer recoverPanic("StyleMaxHeight")
 	style, err := Style.SafeGet(uint64(id), "max-height")
 	if err != nil {
 		Log(LogLevelError, "StyleMaxHeight style error: %v", err)
@@ -97,6 +101,7 @@ func StyleMaxHeight(id C.uint64_t, height C.int) C.uint64_t {
 
 //export StyleInline
 func StyleInline(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleInline")
 	style, err := Style.SafeGet(uint64(id), "inline")
 	if err != nil {
 		Log(LogLevelError, "StyleInline style error: %v", err)
@@ -111,6 +116,7 @@ func StyleInline(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleTabWidth
 func StyleTabWidth(id C.uint64_t, width C.int) C.uint64_t {
+	defer recoverPanic("StyleTabWidth")
 	style, err := Style.SafeGet(uint64(id), "tab-width")
 	if err != nil {
 		Log(LogLevelError, "StyleTabWidth style error: %v", err)
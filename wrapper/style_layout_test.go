@@ -0,0 +1,96 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// styleSetter describes one setter export: apply derives a style from a
+// base style, want is the lipgloss style it should match when rendering,
+// and invalid, if set, calls the export with arguments it must reject.
+type styleSetter struct {
+	name    string
+	apply   func(t *testing.T, base cHandle) cHandle
+	want    lipgloss.Style
+	invalid func(base cHandle) cHandle
+}
+
+// checkSetters checks that each setter renders like lipgloss, rejects bad
+// arguments and freed styles, and leaves the base style untouched
+func checkSetters(t *testing.T, setters []styleSetter) {
+	const text = "Hello\nWorld"
+	for _, s := range setters {
+		t.Run(s.name, func(t *testing.T) {
+			base := keep(t, NewStyle())
+			id := keep(t, s.apply(t, base))
+			if got, want := render(t, id, text), s.want.Render(text); got != want {
+				t.Errorf("rendered %q, want %q", got, want)
+			}
+			if got := render(t, base, text); got != text {
+				t.Errorf("the base style changed and renders %q", got)
+			}
+
+			if s.invalid != nil {
+				expectLog(t, s.name, func() {
+					if got := s.invalid(base); got != 0 {
+						t.Errorf("invalid arguments returned %#x, want 0", got)
+					}
+				})
+			}
+			expectLog(t, "freed", func() {
+				if got := s.apply(t, stale()); got != 0 {
+					t.Errorf("a freed style returned %#x, want 0", got)
+				}
+			})
+		})
+	}
+}
+
+// set returns an apply function for a setter taking one argument
+func set[A any](fn func(cHandle, A) cHandle, arg A) func(*testing.T, cHandle) cHandle {
+	return func(_ *testing.T, id cHandle) cHandle { return fn(id, arg) }
+}
+
+func TestLayoutSetters(t *testing.T) {
+	s := lipgloss.NewStyle()
+	checkSetters(t, []styleSetter{
+		{"StyleWidth", set(StyleWidth, 8), s.Width(8), func(id cHandle) cHandle { return StyleWidth(id, -1) }},
+		{"StyleHeight", set(StyleHeight, 4), s.Height(4), func(id cHandle) cHandle { return StyleHeight(id, -1) }},
+		{"StyleMaxWidth", set(StyleMaxWidth, 3), s.MaxWidth(3), func(id cHandle) cHandle { return StyleMaxWidth(id, -1) }},
+		{"StyleMaxHeight", set(StyleMaxHeight, 1), s.MaxHeight(1), func(id cHandle) cHandle { return StyleMaxHeight(id, -1) }},
+		{"StyleInline", set(StyleInline, 1), s.Inline(true), nil},
+	})
+}
+
+func TestAlignmentSetters(t *testing.T) {
+	s := lipgloss.NewStyle()
+	checkSetters(t, []styleSetter{
+		{"StyleAlignHorizontal", func(t *testing.T, id cHandle) cHandle {
+			if id = StyleWidth(id, 9); id == 0 {
+				return 0
+			}
+			return StyleAlignHorizontal(keep(t, id), 1)
+		}, s.Width(9).Align(lipgloss.Right), func(id cHandle) cHandle { return StyleAlignHorizontal(id, 1.5) }},
+		{"StyleAlignVertical", func(t *testing.T, id cHandle) cHandle {
+			if id = StyleHeight(id, 4); id == 0 {
+				return 0
+			}
+			return StyleAlignVertical(keep(t, id), 0.5)
+		}, s.Height(4).AlignVertical(lipgloss.Center), func(id cHandle) cHandle { return StyleAlignVertical(id, -0.5) }},
+		{"StylePadding", func(_ *testing.T, id cHandle) cHandle { return StylePadding(id, 1, 2, 0, 3) },
+			s.Padding(1, 2, 0, 3), func(id cHandle) cHandle { return StylePadding(id, 1, -2, 0, 3) }},
+		{"StylePaddingTop", set(StylePaddingTop, 1), s.PaddingTop(1), func(id cHandle) cHandle { return StylePaddingTop(id, -1) }},
+		{"StylePaddingRight", set(StylePaddingRight, 2), s.PaddingRight(2), func(id cHandle) cHandle { return StylePaddingRight(id, -1) }},
+		{"StylePaddingBottom", set(StylePaddingBottom, 1), s.PaddingBottom(1), func(id cHandle) cHandle { return StylePaddingBottom(id, -1) }},
+		{"StylePaddingLeft", set(StylePaddingLeft, 2), s.PaddingLeft(2), func(id cHandle) cHandle { return StylePaddingLeft(id, -1) }},
+		{"StyleMargin", func(_ *testing.T, id cHandle) cHandle { return StyleMargin(id, 0, 1, 2, 3) },
+			s.Margin(0, 1, 2, 3), func(id cHandle) cHandle { return StyleMargin(id, 0, 1, -2, 3) }},
+		{"StyleMarginTop", set(StyleMarginTop, 1), s.MarginTop(1), func(id cHandle) cHandle { return StyleMarginTop(id, -1) }},
+		{"StyleMarginRight", set(StyleMarginRight, 2), s.MarginRight(2), func(id cHandle) cHandle { return StyleMarginRight(id, -1) }},
+		{"StyleMarginBottom", set(StyleMarginBottom, 1), s.MarginBottom(1), func(id cHandle) cHandle { return StyleMarginBottom(id, -1) }},
+		{"StyleMarginLeft", set(StyleMarginLeft, 2), s.MarginLeft(2), func(id cHandle) cHandle { return StyleMarginLeft(id, -1) }},
+	})
+}
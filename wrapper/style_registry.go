@@ -8,8 +8,6 @@ package main
 import "C"
 import (
 	"fmt"
-	"sync"
-	"sync/atomic"
 	"unsafe"
 
 	"github.com/charmbracelet/lipgloss"
@@ -17,13 +15,18 @@ import (
 
 // styleRegistry manages style instances with thread safety
 type styleRegistry struct {
-	sync.RWMutex
-	nextID uint64
-	styles map[uint64]*lipgloss.Style
+	handleRegistry[lipgloss.Style]
 }
 
-var styleReg = &styleRegistry{
-	styles: make(map[uint64]*lipgloss.Style),
+var styleReg = &styleRegistry{handleRegistry[lipgloss.Style]{kind: handleKindStyle}}
+
+// Remove frees a style handle and drops its cached renders
+func (r *styleRegistry) Remove(id uint64) *lipgloss.Style {
+	style := r.handleRegistry.Remove(id)
+	if style != nil {
+		renderCache.invalidate(id)
+	}
+	return style
 }
 
 // RegistryError represents an error in registry operations
@@ -34,49 +37,7 @@ type RegistryError struct {
 }
 
 func (e *RegistryError) Error() string {
-	return fmt.Sprintf("registry error (op=%s, id=%d): %s", e.Op, e.ID, e.Message)
-}
-
-// Register adds a style to the registry and returns its ID
-func (r *styleRegistry) Register(style *lipgloss.Style) uint64 {
-	if style == nil {
-		Log(LogLevelError, "Attempted to register nil style")
-		return 0
-	}
-
-	r.Lock()
-	defer r.Unlock()
-
-	id := atomic.AddUint64(&r.nextID, 1)
-	r.styles[id] = style
-	Log(LogLevelDebug, "Registered new style with ID: %d", id)
-	return id
-}
-
-// Get retrieves a style from the registry
-func (r *styleRegistry) Get(id uint64) *lipgloss.Style {
-	r.RLock()
-	defer r.RUnlock()
-
-	style, exists := r.styles[id]
-	if !exists {
-		Log(LogLevelError, "Style not found with ID: %d", id)
-		return nil
-	}
-	return style
-}
-
-// Remove deletes a style from the registry
-func (r *styleRegistry) Remove(id uint64) {
-	r.Lock()
-	defer r.Unlock()
-
-	if _, exists := r.styles[id]; exists {
-		delete(r.styles, id)
-		Log(LogLevelDebug, "Removed style with ID: %d", id)
-	} else {
-		Log(LogLevelWarn, "Attempted to remove non-existent style with ID: %d", id)
-	}
+	return fmt.Sprintf("registry error (op=%s, id=%#x): %s", e.Op, e.ID, e.Message)
 }
 
 // GetStats returns statistics about the registry
@@ -84,11 +45,12 @@ func (r *styleRegistry) GetStats() string {
 	r.RLock()
 	defer r.RUnlock()
 
-	return fmt.Sprintf("Total styles: %d, Next ID: %d", len(r.styles), r.nextID)
+	return fmt.Sprintf("Total styles: %d, Peak styles: %d", len(r.slots)-len(r.free), r.stats.highWater)
 }
 
 //export NewStyle
 func NewStyle() C.uint64_t {
+	defer recoverPanic("NewStyle")
 	style := lipgloss.NewStyle()
 	id := styleReg.Register(&style)
 	Log(LogLevelDebug, "Created new style with ID: %d", id)
@@ -97,6 +59,7 @@ func NewStyle() C.uint64_t {
 
 //export CopyStyle
 func CopyStyle(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("CopyStyle")
 	style := styleReg.Get(uint64(id))
 	if style == nil {
 		Log(LogLevelError, "CopyStyle failed: source style not found with ID: %d", uint64(id))
@@ -111,11 +74,13 @@ func CopyStyle(id C.uint64_t) C.uint64_t {
 
 //export FreeStyle
 func FreeStyle(id C.uint64_t) {
+	defer recoverPanic("FreeStyle")
 	styleReg.Remove(uint64(id))
 }
 
 //export FreeString
 func FreeString(str *C.char) {
+	defer recoverPanic("FreeString")
 	if str != nil {
 		Memory.Untrack(unsafe.Pointer(str))
 		C.free(unsafe.Pointer(str))
@@ -123,12 +88,13 @@ func FreeString(str *C.char) {
 }
 
 //export GetStyleStats
-func GetStyleStats() *C.char {
+func GetStyleStats() (ret *C.char) {
+	defer recoverString("GetStyleStats", &ret, "Error getting stats")
 	stats := styleReg.GetStats()
 	cs, err := String.CString(stats)
 	if err != nil {
 		Log(LogLevelError, "GetStyleStats memory allocation error: %v", err)
-		return C.CString("Error getting stats")
+		return Memory.CString("Error getting stats", "style stats string")
 	}
 	Memory.Track(unsafe.Pointer(cs), "style stats string")
 	return cs
@@ -136,13 +102,5 @@ func GetStyleStats() *C.char {
 
 // Helper function to safely get a style with error handling
 func getStyleSafe(id uint64, op string) (*lipgloss.Style, error) {
-	style := styleReg.Get(id)
-	if style == nil {
-		return nil, &RegistryError{
-			Op:      op,
-			ID:      id,
-			Message: "style not found",
-		}
-	}
-	return style, nil
+	return styleReg.Lookup(id, op)
 }
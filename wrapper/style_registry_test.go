@@ -0,0 +1,59 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStyleLifecycle(t *testing.T) {
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	copied := keep(t, CopyStyle(bold))
+	if copied == bold {
+		t.Error("CopyStyle returned the handle it copied")
+	}
+	if StyleGetBold(copied) != 1 {
+		t.Error("CopyStyle lost the bold property")
+	}
+
+	if stats := takeString(GetStyleStats()); !strings.HasPrefix(stats, "Total styles: ") {
+		t.Errorf("GetStyleStats = %q", stats)
+	}
+
+	expectLog(t, "CopyStyle failed", func() {
+		if got := CopyStyle(stale()); got != 0 {
+			t.Errorf("CopyStyle of a freed style = %#x, want 0", got)
+		}
+	})
+	expectLog(t, "double free", func() { FreeStyle(stale()) })
+	expectLog(t, "null style handle", func() { FreeStyle(0) })
+}
+
+func TestFreeString(t *testing.T) {
+	FreeString(nil)
+
+	cs := takeString(StyleString(keep(t, NewStyle())))
+	if cs != "" {
+		t.Errorf("StyleString of an empty style = %q", cs)
+	}
+	expectLog(t, "StyleString error", func() {
+		if got := takeString(StyleString(stale())); got != "" {
+			t.Errorf("StyleString of a freed style = %q, want empty", got)
+		}
+	})
+}
+
+func TestStyleCleanup(t *testing.T) {
+	report := takeString(StyleCleanup())
+	if report != "No memory leaks detected" {
+		t.Errorf("StyleCleanup with no live strings = %q", report)
+	}
+
+	held := StyleString(keep(t, NewStyle()))
+	report = takeString(StyleCleanup())
+	FreeString(held)
+	if !strings.Contains(report, "StyleString result") {
+		t.Errorf("StyleCleanup did not report a live string: %q", report)
+	}
+}
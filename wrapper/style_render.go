@@ -6,23 +6,35 @@ package main
 #include "lipgloss_types.h"
 */
 import "C"
-import "unsafe"
+import (
+	"unsafe"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// renderStyle renders str with the style behind id
+func renderStyle(id uint64, str string) (string, error) {
+	style, err := Style.SafeGet(id, "render")
+	if err != nil {
+		return "", err
+	}
+	return cachedRender(id, style, str), nil
+}
 
 //export StyleRender
-func StyleRender(id C.uint64_t, str *C.char) *C.char {
-	style, err := Style.SafeGet(uint64(id), "render")
+func StyleRender(id C.uint64_t, str *C.char) (ret *C.char) {
+	defer recoverString("StyleRender", &ret, "")
+	result, err := renderStyle(uint64(id), String.GoString(str))
 	if err != nil {
 		Log(LogLevelError, "StyleRender error: %v", err)
-		cs, _ := String.CString("")
+		cs := Memory.CString("", "StyleRender result")
 		return cs
 	}
 
-	inputStr := String.GoString(str)
-	result := style.Render(inputStr)
 	cs, err := String.CString(result)
 	if err != nil {
 		Log(LogLevelError, "StyleRender memory allocation error: %v", err)
-		cs, _ = String.CString("")
+		cs = Memory.CString("", "StyleRender result")
 		return cs
 	}
 
@@ -30,8 +42,57 @@ func StyleRender(id C.uint64_t, str *C.char) *C.char {
 	return cs
 }
 
+// StyleRenderBatch renders texts[i] with styles[i] for each of the n pairs,
+// resolving every style under one registry lock, and stores the results in
+// out. Each result must be freed with FreeString; pairs whose style cannot
+// be resolved get an empty string. Returns the number of pairs rendered.
+//
+//export StyleRenderBatch
+func StyleRenderBatch(styles *C.CHandleIn, texts *C.CStringIn, n C.size_t, out **C.char) (ret C.size_t) {
+	// On a panic the pairs not rendered yet still get a string to free
+	var results []*C.char
+	filled, rendered := 0, 0
+	defer func() {
+		if r := recover(); r != nil {
+			reportPanic("StyleRenderBatch", r)
+			for ; filled < len(results); filled++ {
+				results[filled] = Memory.CString("", "StyleRenderBatch result")
+			}
+			ret = C.size_t(rendered)
+		}
+	}()
+	if n == 0 {
+		return 0
+	}
+	if styles == nil || texts == nil || out == nil {
+		Log(LogLevelError, "StyleRenderBatch received a NULL array for %d pairs", uint64(n))
+		return 0
+	}
+	results = unsafe.Slice(out, int(n))
+
+	ids := make([]uint64, int(n))
+	for i, id := range unsafe.Slice(styles, int(n)) {
+		ids[i] = uint64(id)
+	}
+	resolved, errs := styleReg.LookupAll(ids, "render-batch")
+
+	inputs := unsafe.Slice(texts, int(n))
+	for i, style := range resolved {
+		filled = i
+		if errs[i] != nil {
+			Log(LogLevelError, "StyleRenderBatch item %d error: %v", i, errs[i])
+			results[i] = Memory.CString("", "StyleRenderBatch result")
+			continue
+		}
+		results[i] = Memory.CString(cachedRender(ids[i], style, String.GoString((*C.char)(unsafe.Pointer(inputs[i])))), "StyleRenderBatch result")
+		rendered++
+	}
+	return C.size_t(rendered)
+}
+
 //export StyleInherited
 func StyleInherited(id C.uint64_t) C.int {
+	defer recoverPanic("StyleInherited")
 	style, err := Style.SafeGet(uint64(id), "inherited")
 	if err != nil {
 		Log(LogLevelError, "StyleInherited error: %v", err)
@@ -44,7 +105,10 @@ func StyleInherited(id C.uint64_t) C.int {
 		border.Left != "" || border.Right != ""
 
 	// Add checks for other inheritable properties
-	hasColor := style.GetForeground() != nil || style.GetBackground() != nil
+	// Unset colors are reported as NoColor, not nil
+	_, noForeground := style.GetForeground().(lipgloss.NoColor)
+	_, noBackground := style.GetBackground().(lipgloss.NoColor)
+	hasColor := !noForeground || !noBackground
 	hasMargin := style.GetMarginTop() != 0 || style.GetMarginRight() != 0 ||
 		style.GetMarginBottom() != 0 || style.GetMarginLeft() != 0
 	hasPadding := style.GetPaddingTop() != 0 || style.GetPaddingRight() != 0 ||
@@ -54,18 +118,19 @@ func StyleInherited(id C.uint64_t) C.int {
 }
 
 //export StyleString
-func StyleString(id C.uint64_t) *C.char {
+func StyleString(id C.uint64_t) (ret *C.char) {
+	defer recoverString("StyleString", &ret, "")
 	style, err := Style.SafeGet(uint64(id), "string")
 	if err != nil {
 		Log(LogLevelError, "StyleString error: %v", err)
-		cs, _ := String.CString("")
+		cs := Memory.CString("", "StyleString result")
 		return cs
 	}
 
 	cs, err := String.CString(style.String())
 	if err != nil {
 		Log(LogLevelError, "StyleString memory allocation error: %v", err)
-		cs, _ = String.CString("")
+		cs = Memory.CString("", "StyleString result")
 		return cs
 	}
 
@@ -75,6 +140,7 @@ func StyleString(id C.uint64_t) *C.char {
 
 //export StyleInherit
 func StyleInherit(baseID, inheritID C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleInherit")
 	baseStyle, err := Style.SafeGet(uint64(baseID), "inherit-base")
 	if err != nil {
 		Log(LogLevelError, "StyleInherit base error: %v", err)
@@ -97,6 +163,7 @@ func StyleInherit(baseID, inheritID C.uint64_t) C.uint64_t {
 // Cleanup helper
 //
 //export StyleCleanup
-func StyleCleanup() *C.char {
+func StyleCleanup() (ret *C.char) {
+	defer recoverString("StyleCleanup", &ret, "")
 	return GetMemoryLeaks()
 }
@@ -0,0 +1,71 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// logViewerSpans builds n spans cycling through a few registered styles,
+// like the lines of a log viewer
+func logViewerSpans(tb testing.TB, n int) *cRenderInputs {
+	colors := []string{"#ff5f87", "#5fafff", "#87d787", "#ffd75f"}
+	palette := make([]uint64, len(colors))
+	for i, color := range colors {
+		style := lipgloss.NewStyle().Foreground(lipgloss.Color(color)).Bold(i%2 == 0)
+		palette[i] = styleReg.Register(&style)
+	}
+
+	styles := make([]uint64, n)
+	texts := make([]string, n)
+	for i := range styles {
+		styles[i] = palette[i%len(palette)]
+		texts[i] = fmt.Sprintf("2024-05-01T12:00:%02d span %d", i%60, i)
+	}
+
+	in := newCRenderInputs(styles, texts)
+	tb.Cleanup(func() {
+		in.free()
+		for _, id := range palette {
+			styleReg.Remove(id)
+		}
+	})
+	return in
+}
+
+func TestStyleRenderBatch(t *testing.T) {
+	in := logViewerSpans(t, 100)
+	if got := in.renderBatch(); got != 100 {
+		t.Fatalf("renderBatch rendered %d pairs, want 100", got)
+	}
+}
+
+func TestStyleRenderBatchErrors(t *testing.T) {
+	if got := StyleRenderBatch(nil, nil, 0, nil); got != 0 {
+		t.Errorf("StyleRenderBatch of 0 pairs = %d", got)
+	}
+	expectLog(t, "NULL array", func() {
+		if got := StyleRenderBatch(nil, nil, 3, nil); got != 0 {
+			t.Errorf("StyleRenderBatch with NULL arrays = %d, want 0", got)
+		}
+	})
+}
+
+func BenchmarkStyleRender(b *testing.B) {
+	in := logViewerSpans(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.renderEach()
+	}
+}
+
+func BenchmarkStyleRenderBatch(b *testing.B) {
+	in := logViewerSpans(b, 1000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.renderBatch()
+	}
+}
@@ -0,0 +1,81 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestStyleRender(t *testing.T) {
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	if got, want := render(t, bold, "hi"), lipgloss.NewStyle().Bold(true).Render("hi"); got != want {
+		t.Errorf("StyleRender = %q, want %q", got, want)
+	}
+
+	expectLog(t, "StyleRender error", func() {
+		if got := render(t, stale(), "hi"); got != "" {
+			t.Errorf("StyleRender with a freed style = %q, want empty", got)
+		}
+	})
+	expectLog(t, "StyleRender error", func() {
+		if got := render(t, 0, "hi"); got != "" {
+			t.Errorf("StyleRender with a null handle = %q, want empty", got)
+		}
+	})
+}
+
+func TestStyleInherit(t *testing.T) {
+	base := keep(t, NewStyle())
+	bold := keep(t, StyleBold(base, 1))
+	padded := keep(t, StylePadding(base, 0, 1, 0, 1))
+	colored := keep(t, StyleForeground(base, cString(t, "#00ff00")))
+
+	if got := StyleInherited(bold); got != 0 {
+		t.Errorf("StyleInherited of a bold style = %d, want 0", got)
+	}
+	if got := StyleInherited(padded); got != 1 {
+		t.Errorf("StyleInherited of a padded style = %d, want 1", got)
+	}
+
+	// Inherit copies unset properties except margins and padding
+	inherited := keep(t, StyleInherit(bold, colored))
+	want := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00ff00"))
+	if got := render(t, inherited, "x"); got != want.Render("x") {
+		t.Errorf("StyleInherit rendered %q, want %q", got, want.Render("x"))
+	}
+
+	expectLog(t, "StyleInherited error", func() {
+		if got := StyleInherited(stale()); got != 0 {
+			t.Errorf("StyleInherited of a freed style = %d", got)
+		}
+	})
+	expectLog(t, "StyleInherit base error", func() {
+		if got := StyleInherit(stale(), colored); got != 0 {
+			t.Errorf("StyleInherit from a freed base = %#x", got)
+		}
+	})
+	expectLog(t, "StyleInherit inherit error", func() {
+		if got := StyleInherit(bold, stale()); got != 0 {
+			t.Errorf("StyleInherit of a freed style = %#x", got)
+		}
+	})
+}
+
+func TestGoldenStyle(t *testing.T) {
+	rounded := RoundedBorder()
+	defer FreeBorder(rounded)
+
+	id := keep(t, NewStyle())
+	id = keep(t, StyleBold(id, 1))
+	id = keep(t, StyleForeground(id, cString(t, "#fafafa")))
+	id = keep(t, StyleBackground(id, cString(t, "#7d56f4")))
+	id = keep(t, StylePadding(id, 1, 2, 1, 2))
+	id = keep(t, StyleWidth(id, 24))
+	id = keep(t, StyleAlignHorizontal(id, 0.5))
+	id = keep(t, StyleBorder(id, rounded))
+	id = keep(t, StyleBorderForeground(id, cString(t, "63")))
+
+	golden(t, "style_card", render(t, id, "Hello, Lipgloss!\nfrom C"))
+}
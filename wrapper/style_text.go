@@ -25,6 +25,7 @@ func (e *TextStyleError) Error() string {
 
 //export StyleSetString
 func StyleSetString(id C.uint64_t, str *C.char) C.uint64_t {
+	defer recoverPanic("StyleSetString")
 	style, err := Style.SafeGet(uint64(id), "set-string")
 	if err != nil {
 		Log(LogLevelError, "StyleSetString style error: %v", err)
@@ -38,19 +39,44 @@ func StyleSetString(id C.uint64_t, str *C.char) C.uint64_t {
 	return id
 }
 
+//export StyleSetStrings
+func StyleSetStrings(id C.uint64_t, strs **C.char, count C.int) C.uint64_t {
+	defer recoverPanic("StyleSetStrings")
+	style, err := Style.SafeGet(uint64(id), "set-strings")
+	if err != nil {
+		Log(LogLevelError, "StyleSetStrings style error: %v", err)
+		return 0
+	}
+	if count < 0 || (count > 0 && strs == nil) {
+		Log(LogLevelError, "StyleSetStrings received invalid string array (count=%d)", int(count))
+		return 0
+	}
+
+	// Like lipgloss's variadic SetString, the strings are joined with spaces
+	goStrs := make([]string, int(count))
+	for i, cs := range unsafe.Slice(strs, int(count)) {
+		goStrs[i] = String.GoString(cs)
+	}
+	newStyle := style.SetString(goStrs...)
+	id = C.uint64_t(styleReg.Register(&newStyle))
+	Log(LogLevelDebug, "Created new style with set strings, ID: %d", uint64(id))
+	return id
+}
+
 //export StyleGetValue
-func StyleGetValue(id C.uint64_t) *C.char {
+func StyleGetValue(id C.uint64_t) (ret *C.char) {
+	defer recoverString("StyleGetValue", &ret, "")
 	style, err := Style.SafeGet(uint64(id), "get-value")
 	if err != nil {
 		Log(LogLevelError, "StyleGetValue style error: %v", err)
-		return C.CString("")
+		return Memory.CString("", "style value string")
 	}
 
 	value := style.Value()
 	cs, err := String.CString(value)
 	if err != nil {
 		Log(LogLevelError, "StyleGetValue memory allocation error: %v", err)
-		return C.CString("")
+		return Memory.CString("", "style value string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "style value string")
@@ -59,6 +85,7 @@ func StyleGetValue(id C.uint64_t) *C.char {
 
 //export StyleBold
 func StyleBold(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleBold")
 	style, err := Style.SafeGet(uint64(id), "bold")
 	if err != nil {
 		Log(LogLevelError, "StyleBold style error: %v", err)
@@ -73,6 +100,7 @@ func StyleBold(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleItalic
 func StyleItalic(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleItalic")
 	style, err := Style.SafeGet(uint64(id), "italic")
 	if err != nil {
 		Log(LogLevelError, "StyleItalic style error: %v", err)
@@ -87,6 +115,7 @@ func StyleItalic(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleUnderline
 func StyleUnderline(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleUnderline")
 	style, err := Style.SafeGet(uint64(id), "underline")
 	if err != nil {
 		Log(LogLevelError, "StyleUnderline style error: %v", err)
@@ -101,6 +130,7 @@ func StyleUnderline(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleStrikethrough
 func StyleStrikethrough(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleStrikethrough")
 	style, err := Style.SafeGet(uint64(id), "strikethrough")
 	if err != nil {
 		Log(LogLevelError, "StyleStrikethrough style error: %v", err)
@@ -115,6 +145,7 @@ func StyleStrikethrough(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleReverse
 func StyleReverse(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleReverse")
 	style, err := Style.SafeGet(uint64(id), "reverse")
 	if err != nil {
 		Log(LogLevelError, "StyleReverse style error: %v", err)
@@ -129,6 +160,7 @@ func StyleReverse(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleBlink
 func StyleBlink(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleBlink")
 	style, err := Style.SafeGet(uint64(id), "blink")
 	if err != nil {
 		Log(LogLevelError, "StyleBlink style error: %v", err)
@@ -143,6 +175,7 @@ func StyleBlink(id C.uint64_t, v C.int) C.uint64_t {
 
 //export StyleFaint
 func StyleFaint(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleFaint")
 	style, err := Style.SafeGet(uint64(id), "faint")
 	if err != nil {
 		Log(LogLevelError, "StyleFaint style error: %v", err)
@@ -155,6 +188,221 @@ func StyleFaint(id C.uint64_t, v C.int) C.uint64_t {
 	return id
 }
 
+//export StyleUnderlineSpaces
+func StyleUnderlineSpaces(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleUnderlineSpaces")
+	style, err := Style.SafeGet(uint64(id), "underline-spaces")
+	if err != nil {
+		Log(LogLevelError, "StyleUnderlineSpaces style error: %v", err)
+		return 0
+	}
+
+	newStyle := style.UnderlineSpaces(String.ToBool(v))
+	id = C.uint64_t(styleReg.Register(&newStyle))
+	Log(LogLevelDebug, "Created new underline-spaces style with ID: %d", uint64(id))
+	return id
+}
+
+//export StyleStrikethroughSpaces
+func StyleStrikethroughSpaces(id C.uint64_t, v C.int) C.uint64_t {
+	defer recoverPanic("StyleStrikethroughSpaces")
+	style, err := Style.SafeGet(uint64(id), "strikethrough-spaces")
+	if err != nil {
+		Log(LogLevelError, "StyleStrikethroughSpaces style error: %v", err)
+		return 0
+	}
+
+	newStyle := style.StrikethroughSpaces(String.ToBool(v))
+	id = C.uint64_t(styleReg.Register(&newStyle))
+	Log(LogLevelDebug, "Created new strikethrough-spaces style with ID: %d", uint64(id))
+	return id
+}
+
+// getTextProperty reads a boolean property for the export name, returning
+// 0 for unknown styles
+func getTextProperty(id C.uint64_t, name, op string, get func(lipgloss.Style) bool) C.int {
+	style, err := Style.SafeGet(uint64(id), op)
+	if err != nil {
+		Log(LogLevelError, "%s style error: %v", name, err)
+		return 0
+	}
+	return String.ToCInt(get(*style))
+}
+
+// unsetTextProperty registers, for the export name, a copy of a style with
+// one property removed, so it falls back to its default or to an inherited
+// value
+func unsetTextProperty(id C.uint64_t, name, op string, unset func(lipgloss.Style) lipgloss.Style) C.uint64_t {
+	style, err := Style.SafeGet(uint64(id), op)
+	if err != nil {
+		Log(LogLevelError, "%s style error: %v", name, err)
+		return 0
+	}
+
+	newStyle := unset(*style)
+	newID := C.uint64_t(styleReg.Register(&newStyle))
+	Log(LogLevelDebug, "Created new style via %s with ID: %d", name, uint64(newID))
+	return newID
+}
+
+//export StyleGetBold
+func StyleGetBold(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetBold")
+	return getTextProperty(id, "StyleGetBold", "get-bold", lipgloss.Style.GetBold)
+}
+
+//export StyleGetItalic
+func StyleGetItalic(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetItalic")
+	return getTextProperty(id, "StyleGetItalic", "get-italic", lipgloss.Style.GetItalic)
+}
+
+//export StyleGetUnderline
+func StyleGetUnderline(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetUnderline")
+	return getTextProperty(id, "StyleGetUnderline", "get-underline", lipgloss.Style.GetUnderline)
+}
+
+//export StyleGetStrikethrough
+func StyleGetStrikethrough(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetStrikethrough")
+	return getTextProperty(id, "StyleGetStrikethrough", "get-strikethrough", lipgloss.Style.GetStrikethrough)
+}
+
+//export StyleGetReverse
+func StyleGetReverse(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetReverse")
+	return getTextProperty(id, "StyleGetReverse", "get-reverse", lipgloss.Style.GetReverse)
+}
+
+//export StyleGetBlink
+func StyleGetBlink(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetBlink")
+	return getTextProperty(id, "StyleGetBlink", "get-blink", lipgloss.Style.GetBlink)
+}
+
+//export StyleGetFaint
+func StyleGetFaint(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetFaint")
+	return getTextProperty(id, "StyleGetFaint", "get-faint", lipgloss.Style.GetFaint)
+}
+
+//export StyleGetUnderlineSpaces
+func StyleGetUnderlineSpaces(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetUnderlineSpaces")
+	return getTextProperty(id, "StyleGetUnderlineSpaces", "get-underline-spaces", lipgloss.Style.GetUnderlineSpaces)
+}
+
+//export StyleGetStrikethroughSpaces
+func StyleGetStrikethroughSpaces(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetStrikethroughSpaces")
+	return getTextProperty(id, "StyleGetStrikethroughSpaces", "get-strikethrough-spaces", lipgloss.Style.GetStrikethroughSpaces)
+}
+
+//export StyleGetColorWhitespace
+func StyleGetColorWhitespace(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetColorWhitespace")
+	return getTextProperty(id, "StyleGetColorWhitespace", "get-color-whitespace", lipgloss.Style.GetColorWhitespace)
+}
+
+//export StyleGetInline
+func StyleGetInline(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetInline")
+	return getTextProperty(id, "StyleGetInline", "get-inline", lipgloss.Style.GetInline)
+}
+
+// StyleGetTabWidth returns the tab width, or TAB_WIDTH_NO_CONVERSION when
+// tabs are kept as is. An unset width reads as 0; rendering then uses 4.
+//
+//export StyleGetTabWidth
+func StyleGetTabWidth(id C.uint64_t) C.int {
+	defer recoverPanic("StyleGetTabWidth")
+	style, err := Style.SafeGet(uint64(id), "get-tab-width")
+	if err != nil {
+		Log(LogLevelError, "StyleGetTabWidth style error: %v", err)
+		return 0
+	}
+	return C.int(style.GetTabWidth())
+}
+
+//export StyleUnsetBold
+func StyleUnsetBold(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetBold")
+	return unsetTextProperty(id, "StyleUnsetBold", "unset-bold", lipgloss.Style.UnsetBold)
+}
+
+//export StyleUnsetItalic
+func StyleUnsetItalic(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetItalic")
+	return unsetTextProperty(id, "StyleUnsetItalic", "unset-italic", lipgloss.Style.UnsetItalic)
+}
+
+//export StyleUnsetUnderline
+func StyleUnsetUnderline(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetUnderline")
+	return unsetTextProperty(id, "StyleUnsetUnderline", "unset-underline", lipgloss.Style.UnsetUnderline)
+}
+
+//export StyleUnsetStrikethrough
+func StyleUnsetStrikethrough(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetStrikethrough")
+	return unsetTextProperty(id, "StyleUnsetStrikethrough", "unset-strikethrough", lipgloss.Style.UnsetStrikethrough)
+}
+
+//export StyleUnsetReverse
+func StyleUnsetReverse(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetReverse")
+	return unsetTextProperty(id, "StyleUnsetReverse", "unset-reverse", lipgloss.Style.UnsetReverse)
+}
+
+//export StyleUnsetBlink
+func StyleUnsetBlink(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetBlink")
+	return unsetTextProperty(id, "StyleUnsetBlink", "unset-blink", lipgloss.Style.UnsetBlink)
+}
+
+//export StyleUnsetFaint
+func StyleUnsetFaint(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetFaint")
+	return unsetTextProperty(id, "StyleUnsetFaint", "unset-faint", lipgloss.Style.UnsetFaint)
+}
+
+//export StyleUnsetUnderlineSpaces
+func StyleUnsetUnderlineSpaces(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetUnderlineSpaces")
+	return unsetTextProperty(id, "StyleUnsetUnderlineSpaces", "unset-underline-spaces", lipgloss.Style.UnsetUnderlineSpaces)
+}
+
+//export StyleUnsetStrikethroughSpaces
+func StyleUnsetStrikethroughSpaces(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetStrikethroughSpaces")
+	return unsetTextProperty(id, "StyleUnsetStrikethroughSpaces", "unset-strikethrough-spaces", lipgloss.Style.UnsetStrikethroughSpaces)
+}
+
+//export StyleUnsetColorWhitespace
+func StyleUnsetColorWhitespace(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetColorWhitespace")
+	return unsetTextProperty(id, "StyleUnsetColorWhitespace", "unset-color-whitespace", lipgloss.Style.UnsetColorWhitespace)
+}
+
+//export StyleUnsetInline
+func StyleUnsetInline(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetInline")
+	return unsetTextProperty(id, "StyleUnsetInline", "unset-inline", lipgloss.Style.UnsetInline)
+}
+
+//export StyleUnsetTabWidth
+func StyleUnsetTabWidth(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetTabWidth")
+	return unsetTextProperty(id, "StyleUnsetTabWidth", "unset-tab-width", lipgloss.Style.UnsetTabWidth)
+}
+
+//export StyleUnsetString
+func StyleUnsetString(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetString")
+	return unsetTextProperty(id, "StyleUnsetString", "unset-string", lipgloss.Style.UnsetString)
+}
+
 // validateTextStyle performs common validation for text style operations
 func validateTextStyle(style *lipgloss.Style, op string) error {
 	if style == nil {
@@ -167,11 +415,12 @@ func validateTextStyle(style *lipgloss.Style, op string) error {
 }
 
 //export GetTextStyleInfo
-func GetTextStyleInfo(id C.uint64_t) *C.char {
+func GetTextStyleInfo(id C.uint64_t) (ret *C.char) {
+	defer recoverString("GetTextStyleInfo", &ret, "")
 	style, err := Style.SafeGet(uint64(id), "get-info")
 	if err != nil {
 		Log(LogLevelError, "GetTextStyleInfo style error: %v", err)
-		return C.CString("Error: Style not found")
+		return Memory.CString("Error: Style not found", "text style info string")
 	}
 
 	info := fmt.Sprintf("Style ID: %d\nBold: %v\nItalic: %v\nUnderline: %v\nStrikethrough: %v\n",
@@ -184,7 +433,7 @@ func GetTextStyleInfo(id C.uint64_t) *C.char {
 	cs, err := String.CString(info)
 	if err != nil {
 		Log(LogLevelError, "GetTextStyleInfo memory allocation error: %v", err)
-		return C.CString("Error: Memory allocation failed")
+		return Memory.CString("Error: Memory allocation failed", "text style info string")
 	}
 
 	Memory.Track(unsafe.Pointer(cs), "text style info string")
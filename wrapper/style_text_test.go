@@ -0,0 +1,166 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+var textProperties = []struct {
+	name  string
+	set   func(cHandle, cInt) cHandle
+	get   func(cHandle) cInt
+	unset func(cHandle) cHandle
+}{
+	{"Bold", StyleBold, StyleGetBold, StyleUnsetBold},
+	{"Italic", StyleItalic, StyleGetItalic, StyleUnsetItalic},
+	{"Underline", StyleUnderline, StyleGetUnderline, StyleUnsetUnderline},
+	{"Strikethrough", StyleStrikethrough, StyleGetStrikethrough, StyleUnsetStrikethrough},
+	{"Reverse", StyleReverse, StyleGetReverse, StyleUnsetReverse},
+	{"Blink", StyleBlink, StyleGetBlink, StyleUnsetBlink},
+	{"Faint", StyleFaint, StyleGetFaint, StyleUnsetFaint},
+	{"UnderlineSpaces", StyleUnderlineSpaces, StyleGetUnderlineSpaces, StyleUnsetUnderlineSpaces},
+	{"StrikethroughSpaces", StyleStrikethroughSpaces, StyleGetStrikethroughSpaces, StyleUnsetStrikethroughSpaces},
+	{"ColorWhitespace", StyleColorWhitespace, StyleGetColorWhitespace, StyleUnsetColorWhitespace},
+	{"Inline", StyleInline, StyleGetInline, StyleUnsetInline},
+}
+
+func TestTextProperties(t *testing.T) {
+	for _, p := range textProperties {
+		t.Run(p.name, func(t *testing.T) {
+			base := keep(t, NewStyle())
+			on := keep(t, p.set(base, 1))
+			if got := p.get(on); got != 1 {
+				t.Errorf("Get%s after setting it = %d, want 1", p.name, got)
+			}
+			if got := p.get(base); got != 0 {
+				t.Errorf("setting %s changed the original style", p.name)
+			}
+			off := keep(t, p.unset(on))
+			if got := p.get(off); got != 0 {
+				t.Errorf("Get%s after unsetting it = %d, want 0", p.name, got)
+			}
+
+			id := stale()
+			expectLog(t, "freed", func() {
+				if got := p.set(id, 1); got != 0 {
+					t.Errorf("Style%s on a freed style = %#x, want 0", p.name, got)
+				}
+			})
+			expectLog(t, "freed", func() {
+				if got := p.get(id); got != 0 {
+					t.Errorf("StyleGet%s on a freed style = %d, want 0", p.name, got)
+				}
+			})
+			expectLog(t, "freed", func() {
+				if got := p.unset(id); got != 0 {
+					t.Errorf("StyleUnset%s on a freed style = %#x, want 0", p.name, got)
+				}
+			})
+		})
+	}
+}
+
+func TestStyleStrings(t *testing.T) {
+	base := keep(t, NewStyle())
+
+	withString := keep(t, StyleSetString(base, cString(t, "hello")))
+	if got := takeString(StyleGetValue(withString)); got != "hello" {
+		t.Errorf("StyleGetValue = %q, want hello", got)
+	}
+	if got := takeString(StyleString(withString)); got != "hello" {
+		t.Errorf("StyleString = %q, want hello", got)
+	}
+
+	withStrings := keep(t, StyleSetStrings(base, cStrings(t, []string{"a", "b", "c"}), 3))
+	if got := takeString(StyleGetValue(withStrings)); got != "a b c" {
+		t.Errorf("StyleGetValue after StyleSetStrings = %q, want %q", got, "a b c")
+	}
+	cleared := keep(t, StyleUnsetString(withStrings))
+	if got := takeString(StyleGetValue(cleared)); got != "" {
+		t.Errorf("StyleGetValue after StyleUnsetString = %q, want empty", got)
+	}
+
+	expectLog(t, "invalid string array", func() {
+		if got := StyleSetStrings(base, nil, 2); got != 0 {
+			t.Errorf("StyleSetStrings(NULL, 2) = %#x, want 0", got)
+		}
+	})
+	expectLog(t, "invalid string array", func() {
+		if got := StyleSetStrings(base, nil, -1); got != 0 {
+			t.Errorf("StyleSetStrings with a negative count = %#x, want 0", got)
+		}
+	})
+
+	id := stale()
+	for name, fn := range map[string]func() cHandle{
+		"StyleSetString":   func() cHandle { return StyleSetString(id, cString(t, "x")) },
+		"StyleSetStrings":  func() cHandle { return StyleSetStrings(id, cStrings(t, []string{"x"}), 1) },
+		"StyleUnsetString": func() cHandle { return StyleUnsetString(id) },
+	} {
+		expectLog(t, "freed", func() {
+			if got := fn(); got != 0 {
+				t.Errorf("%s on a freed style = %#x, want 0", name, got)
+			}
+		})
+	}
+	expectLog(t, "StyleGetValue", func() {
+		if got := takeString(StyleGetValue(id)); got != "" {
+			t.Errorf("StyleGetValue on a freed style = %q, want empty", got)
+		}
+	})
+}
+
+func TestStyleTabWidth(t *testing.T) {
+	base := keep(t, NewStyle())
+	if got := StyleGetTabWidth(base); got != 0 {
+		t.Errorf("StyleGetTabWidth of a new style = %d, want 0 (unset)", got)
+	}
+
+	two := keep(t, StyleTabWidth(base, 2))
+	if got := StyleGetTabWidth(two); got != 2 {
+		t.Errorf("StyleGetTabWidth = %d, want 2", got)
+	}
+	if got := render(t, two, "a\tb"); got != "a  b" {
+		t.Errorf("rendering with tab width 2 = %q", got)
+	}
+
+	keepTabs := keep(t, StyleTabWidth(base, -1))
+	if got := StyleGetTabWidth(keepTabs); got != -1 {
+		t.Errorf("StyleGetTabWidth = %d, want -1 (no conversion)", got)
+	}
+	if got := render(t, keepTabs, "a\tb"); got != "a\tb" {
+		t.Errorf("rendering without tab conversion = %q", got)
+	}
+
+	unset := keep(t, StyleUnsetTabWidth(two))
+	if got := StyleGetTabWidth(unset); got != 0 {
+		t.Errorf("StyleGetTabWidth after StyleUnsetTabWidth = %d, want 0", got)
+	}
+
+	expectLog(t, "StyleTabWidth validation error", func() {
+		if got := StyleTabWidth(base, -2); got != 0 {
+			t.Errorf("StyleTabWidth(-2) = %#x, want 0", got)
+		}
+	})
+	expectLog(t, "StyleGetTabWidth", func() {
+		if got := StyleGetTabWidth(stale()); got != 0 {
+			t.Errorf("StyleGetTabWidth of a freed style = %d, want 0", got)
+		}
+	})
+}
+
+func TestGetTextStyleInfo(t *testing.T) {
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	info := takeString(GetTextStyleInfo(bold))
+	if !strings.Contains(info, "Bold: true") || !strings.Contains(info, "Italic: false") {
+		t.Errorf("GetTextStyleInfo = %q", info)
+	}
+
+	expectLog(t, "GetTextStyleInfo", func() {
+		if got := takeString(GetTextStyleInfo(stale())); !strings.HasPrefix(got, "Error") {
+			t.Errorf("GetTextStyleInfo of a freed style = %q, want an error", got)
+		}
+	})
+}
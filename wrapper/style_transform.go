@@ -0,0 +1,63 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+
+static inline char* callTransform(CTransformFunc fn, const char* content, void* userdata) {
+	return fn(content, userdata);
+}
+*/
+import "C"
+import "unsafe"
+
+// transformFunc adapts a C transform callback to lipgloss. The content is
+// passed as a temporary C string and the returned buffer is freed here. A
+// callback may also rewrite the content in place and return it, which is
+// then freed only once.
+func transformFunc(fn C.CTransformFunc, userdata unsafe.Pointer) func(string) string {
+	return func(content string) string {
+		cs := C.CString(content)
+		defer C.free(unsafe.Pointer(cs))
+
+		out := C.callTransform(fn, cs, userdata)
+		if out == nil {
+			return content
+		}
+		if out != cs {
+			defer C.free(unsafe.Pointer(out))
+		}
+		return C.GoString(out)
+	}
+}
+
+//export StyleTransform
+func StyleTransform(id C.uint64_t, fn C.CTransformFunc, userdata unsafe.Pointer) C.uint64_t {
+	defer recoverPanic("StyleTransform")
+	style, err := Style.SafeGet(uint64(id), "transform")
+	if err != nil {
+		Log(LogLevelError, "StyleTransform style error: %v", err)
+		return 0
+	}
+	if fn == nil {
+		Log(LogLevelError, "StyleTransform received nil callback")
+		return 0
+	}
+
+	newStyle := style.Transform(transformFunc(fn, userdata))
+	return C.uint64_t(Style.Register(&newStyle))
+}
+
+//export StyleUnsetTransform
+func StyleUnsetTransform(id C.uint64_t) C.uint64_t {
+	defer recoverPanic("StyleUnsetTransform")
+	style, err := Style.SafeGet(uint64(id), "unset-transform")
+	if err != nil {
+		Log(LogLevelError, "StyleUnsetTransform style error: %v", err)
+		return 0
+	}
+
+	newStyle := style.UnsetTransform()
+	return C.uint64_t(Style.Register(&newStyle))
+}
@@ -0,0 +1,42 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import "testing"
+
+func TestStyleTransform(t *testing.T) {
+	base := keep(t, NewStyle())
+	upper := keep(t, StyleTransform(base, upperTransformFunc(), nil))
+	if got := render(t, upper, "hello"); got != "HELLO" {
+		t.Errorf("a transformed style renders %q, want HELLO", got)
+	}
+	if got := render(t, base, "hello"); got != "hello" {
+		t.Errorf("StyleTransform changed the original style, which renders %q", got)
+	}
+	plain := keep(t, StyleUnsetTransform(upper))
+	if got := render(t, plain, "hello"); got != "hello" {
+		t.Errorf("after StyleUnsetTransform the style renders %q", got)
+	}
+
+	// A callback that rewrites its argument in place and returns it
+	masked := keep(t, StyleTransform(base, maskTransformFunc(), nil))
+	if got := render(t, masked, "secret"); got != "******" {
+		t.Errorf("an in-place transform renders %q, want ******", got)
+	}
+
+	expectLog(t, "StyleTransform received nil callback", func() {
+		if got := StyleTransform(base, nil, nil); got != 0 {
+			t.Errorf("StyleTransform(NULL) = %#x, want 0", got)
+		}
+	})
+	expectLog(t, "StyleTransform style error", func() {
+		if got := StyleTransform(stale(), upperTransformFunc(), nil); got != 0 {
+			t.Errorf("StyleTransform on a freed style = %#x, want 0", got)
+		}
+	})
+	expectLog(t, "StyleUnsetTransform style error", func() {
+		if got := StyleUnsetTransform(stale()); got != 0 {
+			t.Errorf("StyleUnsetTransform on a freed style = %#x, want 0", got)
+		}
+	})
+}
@@ -7,8 +7,6 @@ package main
 */
 import "C"
 import (
-	"sync"
-	"sync/atomic"
 	"unsafe"
 
 	"github.com/charmbracelet/lipgloss"
@@ -17,50 +15,21 @@ import (
 
 // tableRegistry manages table instances with thread safety
 type tableRegistry struct {
-	sync.RWMutex
-	nextID uint64
-	tables map[uint64]*table.Table
+	handleRegistry[table.Table]
 }
 
-var tableReg = &tableRegistry{
-	tables: make(map[uint64]*table.Table),
-}
-
-// Register adds a table to the registry and returns its ID
-func (r *tableRegistry) Register(t *table.Table) uint64 {
-	if t == nil {
-		return 0
-	}
-	r.Lock()
-	defer r.Unlock()
-
-	id := atomic.AddUint64(&r.nextID, 1)
-	r.tables[id] = t
-	return id
-}
-
-// Get retrieves a table from the registry
-func (r *tableRegistry) Get(id uint64) *table.Table {
-	r.RLock()
-	defer r.RUnlock()
-	return r.tables[id]
-}
-
-// Remove deletes a table from the registry
-func (r *tableRegistry) Remove(id uint64) {
-	r.Lock()
-	defer r.Unlock()
-	delete(r.tables, id)
-}
+var tableReg = &tableRegistry{handleRegistry[table.Table]{kind: handleKindTable}}
 
 //export NewTable
 func NewTable() C.uint64_t {
+	defer recoverPanic("NewTable")
 	t := table.New()
 	return C.uint64_t(tableReg.Register(t))
 }
 
 //export TableAddHeaders
 func TableAddHeaders(id C.uint64_t, headers **C.char, count C.int) {
+	defer recoverPanic("TableAddHeaders")
 	t := tableReg.Get(uint64(id))
 	if t == nil {
 		return
@@ -74,6 +43,7 @@ func TableAddHeaders(id C.uint64_t, headers **C.char, count C.int) {
 
 //export TableAddRow
 func TableAddRow(id C.uint64_t, row **C.char, count C.int) {
+	defer recoverPanic("TableAddRow")
 	t := tableReg.Get(uint64(id))
 	if t == nil {
 		return
@@ -87,6 +57,7 @@ func TableAddRow(id C.uint64_t, row **C.char, count C.int) {
 
 //export TableSetWidth
 func TableSetWidth(id C.uint64_t, width C.int) {
+	defer recoverPanic("TableSetWidth")
 	t := tableReg.Get(uint64(id))
 	if t != nil {
 		t.Width(int(width))
@@ -95,6 +66,7 @@ func TableSetWidth(id C.uint64_t, width C.int) {
 
 //export TableSetHeight
 func TableSetHeight(id C.uint64_t, height C.int) {
+	defer recoverPanic("TableSetHeight")
 	t := tableReg.Get(uint64(id))
 	if t != nil {
 		t.Height(int(height))
@@ -103,6 +75,7 @@ func TableSetHeight(id C.uint64_t, height C.int) {
 
 //export TableSetBorder
 func TableSetBorder(id C.uint64_t, borderType C.int) {
+	defer recoverPanic("TableSetBorder")
 	t := tableReg.Get(uint64(id))
 	if t == nil {
 		return
@@ -117,17 +90,28 @@ func TableSetBorder(id C.uint64_t, borderType C.int) {
 	}
 }
 
+// renderTable renders the table behind id
+func renderTable(id uint64) (string, error) {
+	t, err := tableReg.Lookup(id, "render-table")
+	if err != nil {
+		return "", err
+	}
+	return t.Render(), nil
+}
+
 //export RenderTable
-func RenderTable(id C.uint64_t) *C.char {
-	t := tableReg.Get(uint64(id))
-	if t == nil {
-		return C.CString("")
+func RenderTable(id C.uint64_t) (ret *C.char) {
+	defer recoverString("RenderTable", &ret, "")
+	result, err := renderTable(uint64(id))
+	if err != nil {
+		Log(LogLevelError, "RenderTable error: %v", err)
+		return Memory.CString("", "RenderTable result")
 	}
-	result := t.Render()
-	return C.CString(result)
+	return Memory.CString(result, "RenderTable result")
 }
 
 //export FreeTable
 func FreeTable(id C.uint64_t) {
+	defer recoverPanic("FreeTable")
 	tableReg.Remove(uint64(id))
 }
@@ -0,0 +1,68 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestTable returns a table with a header and two rows, freed when the
+// test ends
+func newTestTable(t testing.TB) cHandle {
+	id := NewTable()
+	t.Cleanup(func() { FreeTable(id) })
+	TableAddHeaders(id, cStrings(t, []string{"Language", "Greeting"}), 2)
+	TableAddRow(id, cStrings(t, []string{"English", "Hello"}), 2)
+	TableAddRow(id, cStrings(t, []string{"Japanese", "こんにちは"}), 2)
+	return id
+}
+
+func TestRenderTable(t *testing.T) {
+	table := newTestTable(t)
+	golden(t, "table_default", takeString(RenderTable(table)))
+
+	TableSetBorder(table, 0)
+	TableSetWidth(table, 30)
+	golden(t, "table_normal_wide", takeString(RenderTable(table)))
+
+	TableSetBorder(table, 1)
+	if got := takeString(RenderTable(table)); !strings.Contains(got, "╭") {
+		t.Errorf("TableSetBorder(1) did not switch to the rounded border:\n%s", got)
+	}
+
+	TableSetBorder(table, 2)
+	TableSetHeight(table, 4)
+	if got := takeString(RenderTable(table)); !strings.Contains(got, "┏") {
+		t.Errorf("TableSetBorder(2) did not switch to the thick border:\n%s", got)
+	}
+
+	// Unknown border types are ignored
+	TableSetBorder(table, 99)
+	if got := takeString(RenderTable(table)); !strings.Contains(got, "┏") {
+		t.Errorf("TableSetBorder(99) changed the border:\n%s", got)
+	}
+}
+
+func TestTableErrors(t *testing.T) {
+	id := NewTable()
+	FreeTable(id)
+
+	for name, fn := range map[string]func(){
+		"TableAddHeaders": func() { TableAddHeaders(id, cStrings(t, []string{"x"}), 1) },
+		"TableAddRow":     func() { TableAddRow(id, cStrings(t, []string{"x"}), 1) },
+		"TableSetWidth":   func() { TableSetWidth(id, 10) },
+		"TableSetHeight":  func() { TableSetHeight(id, 10) },
+		"TableSetBorder":  func() { TableSetBorder(id, 1) },
+		"FreeTable":       func() { FreeTable(id) },
+	} {
+		if got := logged(fn); !strings.Contains(got, "table handle") {
+			t.Errorf("%s on a freed table logged %q", name, got)
+		}
+	}
+	expectLog(t, "RenderTable error", func() {
+		if got := takeString(RenderTable(id)); got != "" {
+			t.Errorf("RenderTable of a freed table = %q", got)
+		}
+	})
+}
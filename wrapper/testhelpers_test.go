@@ -0,0 +1,11 @@
+//go:build !lipgloss_testhelpers
+
+package main
+
+import "testing"
+
+// The tests call the exports through C helpers that are left out of the
+// library unless the lipgloss_testhelpers tag is set
+func TestBuildTag(t *testing.T) {
+	t.Fatal("run the tests with -tags lipgloss_testhelpers, as make test does")
+}
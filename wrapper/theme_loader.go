@@ -0,0 +1,826 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+
+	"github.com/BurntSushi/toml"
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeError reports a problem in a theme document. Path points at the
+// offending value, e.g. "styles.title.padding[2]", and is empty for syntax
+// errors, whose message carries the line and column instead.
+type ThemeError struct {
+	Path    string
+	Message string
+}
+
+func (e *ThemeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("theme error: %s", e.Message)
+	}
+	return fmt.Sprintf("theme error at %s: %s", e.Path, e.Message)
+}
+
+func themeErrorf(path, format string, args ...interface{}) error {
+	return &ThemeError{Path: path, Message: fmt.Sprintf(format, args...)}
+}
+
+// decodeThemeDocument parses source into generic maps and slices
+func decodeThemeDocument(source []byte, format int) (map[string]interface{}, error) {
+	if format == C.THEME_FORMAT_AUTO {
+		format = detectThemeFormat(source)
+	}
+
+	var doc interface{}
+	switch format {
+	case C.THEME_FORMAT_JSON:
+		if err := json.Unmarshal(source, &doc); err != nil {
+			var syntaxErr *json.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				line, col := offsetPosition(source, syntaxErr.Offset)
+				return nil, themeErrorf("", "line %d, column %d: %v", line, col, err)
+			}
+			return nil, themeErrorf("", "%v", err)
+		}
+	case C.THEME_FORMAT_TOML:
+		var table map[string]interface{}
+		if err := toml.Unmarshal(source, &table); err != nil {
+			var parseErr toml.ParseError
+			if errors.As(err, &parseErr) {
+				return nil, themeErrorf("", "line %d, column %d: %s",
+					parseErr.Position.Line, parseErr.Position.Col, parseErr.Message)
+			}
+			return nil, themeErrorf("", "%v", err)
+		}
+		doc = table
+	case C.THEME_FORMAT_YAML:
+		if err := yaml.Unmarshal(source, &doc); err != nil {
+			return nil, themeErrorf("", "%s", strings.TrimPrefix(err.Error(), "yaml: "))
+		}
+	default:
+		return nil, themeErrorf("", "unknown theme format %d", format)
+	}
+
+	root, ok := themeMap(doc)
+	if !ok {
+		return nil, themeErrorf("", "document must be an object with a \"styles\" table")
+	}
+	return root, nil
+}
+
+// detectThemeFormat guesses the format of a document from its first
+// meaningful line
+func detectThemeFormat(source []byte) int {
+	trimmed := bytes.TrimSpace(source)
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return C.THEME_FORMAT_JSON
+	}
+	for _, line := range strings.Split(string(trimmed), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' || strings.Contains(line, "=") && !strings.Contains(line, ":") {
+			return C.THEME_FORMAT_TOML
+		}
+		break
+	}
+	return C.THEME_FORMAT_YAML
+}
+
+// themeFormatForPath picks a format from a file extension
+func themeFormatForPath(path string) int {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return C.THEME_FORMAT_JSON
+	case ".toml":
+		return C.THEME_FORMAT_TOML
+	case ".yaml", ".yml":
+		return C.THEME_FORMAT_YAML
+	}
+	return C.THEME_FORMAT_AUTO
+}
+
+func offsetPosition(source []byte, offset int64) (int, int) {
+	if offset > int64(len(source)) {
+		offset = int64(len(source))
+	}
+	before := source[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	col := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// themeMap normalizes the map types produced by the different decoders
+func themeMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case map[string]interface{}:
+		return m, true
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(m))
+		for k, v := range m {
+			out[fmt.Sprint(k)] = v
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func describeThemeValue(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}, map[interface{}]interface{}:
+		return "object"
+	}
+	return "number"
+}
+
+func themeBool(v interface{}, path string) (bool, error) {
+	b, ok := v.(bool)
+	if !ok {
+		return false, themeErrorf(path, "expected boolean, got %s", describeThemeValue(v))
+	}
+	return b, nil
+}
+
+func themeString(v interface{}, path string) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", themeErrorf(path, "expected string, got %s", describeThemeValue(v))
+	}
+	return s, nil
+}
+
+func themeNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// themeInt reads a non-negative integer
+func themeInt(v interface{}, path string) (int, error) {
+	n, ok := themeNumber(v)
+	if !ok {
+		return 0, themeErrorf(path, "expected non-negative integer, got %s", describeThemeValue(v))
+	}
+	if n < 0 || n != math.Trunc(n) || n > math.MaxInt32 {
+		return 0, themeErrorf(path, "expected non-negative integer, got %v", n)
+	}
+	return int(n), nil
+}
+
+// themeSides reads a CSS-style shorthand: a single integer or an array of
+// one to four integers, as accepted by Style.Padding and Style.Margin
+func themeSides(v interface{}, path string) ([]int, error) {
+	list, ok := v.([]interface{})
+	if !ok {
+		n, err := themeInt(v, path)
+		if err != nil {
+			return nil, err
+		}
+		return []int{n}, nil
+	}
+	if len(list) == 0 || len(list) > 4 {
+		return nil, themeErrorf(path, "expected 1 to 4 values, got %d", len(list))
+	}
+	sides := make([]int, len(list))
+	for i, item := range list {
+		n, err := themeInt(item, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return nil, err
+		}
+		sides[i] = n
+	}
+	return sides, nil
+}
+
+func themeColorString(v interface{}, path string) (string, error) {
+	if n, ok := themeNumber(v); ok {
+		if n < 0 || n > 255 || n != math.Trunc(n) {
+			return "", themeErrorf(path, "ANSI color must be an integer between 0 and 255, got %v", n)
+		}
+		return fmt.Sprintf("%d", int(n)), nil
+	}
+	s, err := themeString(v, path)
+	if err != nil {
+		return "", err
+	}
+	if err := Validate.Color(s, path); err != nil {
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			return "", themeErrorf(path, "%s", validationErr.Message)
+		}
+		return "", themeErrorf(path, "%v", err)
+	}
+	return s, nil
+}
+
+// themeCompleteColor reads {"true_color": ..., "ansi256": ..., "ansi": ...}
+func themeCompleteColor(m map[string]interface{}, path string) (lipgloss.CompleteColor, error) {
+	var c lipgloss.CompleteColor
+	for _, key := range sortedKeys(m) {
+		if key != "true_color" && key != "ansi256" && key != "ansi" {
+			return c, themeErrorf(path+"."+key, "unknown color key (expected true_color, ansi256 or ansi)")
+		}
+		value, err := themeColorString(m[key], path+"."+key)
+		if err != nil {
+			return c, err
+		}
+		switch key {
+		case "true_color":
+			c.TrueColor = value
+		case "ansi256":
+			c.ANSI256 = value
+		case "ansi":
+			c.ANSI = value
+		}
+	}
+	return c, nil
+}
+
+// themeColor reads a plain color ("#ff00ff", "212" or 212), an adaptive
+// color {"light": ..., "dark": ...}, a complete color
+// {"true_color": ..., "ansi256": ..., "ansi": ...} or an adaptive pair of
+// complete colors
+func themeColor(v interface{}, path string) (lipgloss.TerminalColor, error) {
+	m, ok := themeMap(v)
+	if !ok {
+		s, err := themeColorString(v, path)
+		if err != nil {
+			return nil, err
+		}
+		return lipgloss.Color(s), nil
+	}
+
+	_, hasLight := m["light"]
+	_, hasDark := m["dark"]
+	if !hasLight && !hasDark {
+		return themeCompleteColor(m, path)
+	}
+	if !hasLight || !hasDark || len(m) != 2 {
+		return nil, themeErrorf(path, "adaptive colors need exactly \"light\" and \"dark\"")
+	}
+
+	lightMap, lightComplete := themeMap(m["light"])
+	darkMap, darkComplete := themeMap(m["dark"])
+	if lightComplete != darkComplete {
+		return nil, themeErrorf(path, "\"light\" and \"dark\" must both be plain or both be complete colors")
+	}
+	if lightComplete {
+		light, err := themeCompleteColor(lightMap, path+".light")
+		if err != nil {
+			return nil, err
+		}
+		dark, err := themeCompleteColor(darkMap, path+".dark")
+		if err != nil {
+			return nil, err
+		}
+		return lipgloss.CompleteAdaptiveColor{Light: light, Dark: dark}, nil
+	}
+
+	light, err := themeColorString(m["light"], path+".light")
+	if err != nil {
+		return nil, err
+	}
+	dark, err := themeColorString(m["dark"], path+".dark")
+	if err != nil {
+		return nil, err
+	}
+	return lipgloss.AdaptiveColor{Light: light, Dark: dark}, nil
+}
+
+// themeBorders maps border names to lipgloss borders
+var themeBorders = map[string]func() lipgloss.Border{
+	"normal":           lipgloss.NormalBorder,
+	"rounded":          lipgloss.RoundedBorder,
+	"thick":            lipgloss.ThickBorder,
+	"double":           lipgloss.DoubleBorder,
+	"block":            lipgloss.BlockBorder,
+	"hidden":           lipgloss.HiddenBorder,
+	"outer_half_block": lipgloss.OuterHalfBlockBorder,
+	"inner_half_block": lipgloss.InnerHalfBlockBorder,
+}
+
+// themeBorderChars maps the keys of a custom border object to its parts
+var themeBorderChars = []struct {
+	name string
+	part func(*lipgloss.Border) *string
+}{
+	{"top", func(b *lipgloss.Border) *string { return &b.Top }},
+	{"bottom", func(b *lipgloss.Border) *string { return &b.Bottom }},
+	{"left", func(b *lipgloss.Border) *string { return &b.Left }},
+	{"right", func(b *lipgloss.Border) *string { return &b.Right }},
+	{"top_left", func(b *lipgloss.Border) *string { return &b.TopLeft }},
+	{"top_right", func(b *lipgloss.Border) *string { return &b.TopRight }},
+	{"bottom_left", func(b *lipgloss.Border) *string { return &b.BottomLeft }},
+	{"bottom_right", func(b *lipgloss.Border) *string { return &b.BottomRight }},
+	{"middle_left", func(b *lipgloss.Border) *string { return &b.MiddleLeft }},
+	{"middle_right", func(b *lipgloss.Border) *string { return &b.MiddleRight }},
+	{"middle", func(b *lipgloss.Border) *string { return &b.Middle }},
+	{"middle_top", func(b *lipgloss.Border) *string { return &b.MiddleTop }},
+	{"middle_bottom", func(b *lipgloss.Border) *string { return &b.MiddleBottom }},
+}
+
+// themeBorderStyle reads a border name or an object of border characters
+func themeBorderStyle(v interface{}, path string) (lipgloss.Border, error) {
+	if m, ok := themeMap(v); ok {
+		var border lipgloss.Border
+		for _, key := range sortedKeys(m) {
+			found := false
+			for _, c := range themeBorderChars {
+				if c.name != key {
+					continue
+				}
+				value, err := themeString(m[key], path+"."+key)
+				if err != nil {
+					return border, err
+				}
+				*c.part(&border) = value
+				found = true
+			}
+			if !found {
+				return border, themeErrorf(path+"."+key, "unknown border character")
+			}
+		}
+		return border, nil
+	}
+
+	name, err := themeString(v, path)
+	if err != nil {
+		return lipgloss.Border{}, err
+	}
+	border, ok := themeBorders[strings.ToLower(name)]
+	if !ok {
+		names := make([]string, 0, len(themeBorders))
+		for n := range themeBorders {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return lipgloss.Border{}, themeErrorf(path, "unknown border %q (expected one of %s)", name, strings.Join(names, ", "))
+	}
+	return border(), nil
+}
+
+// applyThemeBorder handles "border": either a border name, drawn on all
+// sides, or an object with an optional "style", per-side booleans and colors. Once any
+// side is listed in the object, unlisted sides are not drawn.
+func applyThemeBorder(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+	m, ok := themeMap(v)
+	if !ok {
+		border, err := themeBorderStyle(v, path)
+		if err != nil {
+			return s, err
+		}
+		return s.Border(border), nil
+	}
+
+	if v, ok := m["style"]; ok {
+		border, err := themeBorderStyle(v, path+".style")
+		if err != nil {
+			return s, err
+		}
+		s = s.BorderStyle(border)
+	}
+
+	for _, key := range sortedKeys(m) {
+		keyPath := path + "." + key
+		switch key {
+		case "style":
+		case "top", "right", "bottom", "left":
+			on, err := themeBool(m[key], keyPath)
+			if err != nil {
+				return s, err
+			}
+			switch key {
+			case "top":
+				s = s.BorderTop(on)
+			case "right":
+				s = s.BorderRight(on)
+			case "bottom":
+				s = s.BorderBottom(on)
+			case "left":
+				s = s.BorderLeft(on)
+			}
+		case "foreground", "background":
+			c, err := themeColor(m[key], keyPath)
+			if err != nil {
+				return s, err
+			}
+			if key == "foreground" {
+				s = s.BorderForeground(c)
+			} else {
+				s = s.BorderBackground(c)
+			}
+		default:
+			return s, themeErrorf(keyPath, "unknown border property")
+		}
+	}
+	return s, nil
+}
+
+func themePosition(v interface{}, path string, names map[string]lipgloss.Position) (lipgloss.Position, error) {
+	if n, ok := themeNumber(v); ok {
+		if err := Validate.Position(n, "alignment"); err != nil {
+			return 0, themeErrorf(path, "%v", err)
+		}
+		return lipgloss.Position(n), nil
+	}
+	name, err := themeString(v, path)
+	if err != nil {
+		return 0, err
+	}
+	pos, ok := names[strings.ToLower(name)]
+	if !ok {
+		return 0, themeErrorf(path, "unknown alignment %q", name)
+	}
+	return pos, nil
+}
+
+var (
+	themeHorizontal = map[string]lipgloss.Position{"left": lipgloss.Left, "center": lipgloss.Center, "right": lipgloss.Right}
+	themeVertical   = map[string]lipgloss.Position{"top": lipgloss.Top, "center": lipgloss.Center, "bottom": lipgloss.Bottom}
+)
+
+type themeApplyFunc func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error)
+
+func themeBoolProperty(set func(lipgloss.Style, bool) lipgloss.Style) themeApplyFunc {
+	return func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		b, err := themeBool(v, path)
+		if err != nil {
+			return s, err
+		}
+		return set(s, b), nil
+	}
+}
+
+func themeIntProperty(set func(lipgloss.Style, int) lipgloss.Style) themeApplyFunc {
+	return func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		n, err := themeInt(v, path)
+		if err != nil {
+			return s, err
+		}
+		return set(s, n), nil
+	}
+}
+
+// applyThemeTabWidth reads a tab width, which unlike other sizes may be -1
+// (TAB_WIDTH_NO_CONVERSION) to keep tabs as they are
+func applyThemeTabWidth(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+	if n, ok := themeNumber(v); ok && n == lipgloss.NoTabConversion {
+		return s.TabWidth(lipgloss.NoTabConversion), nil
+	}
+	n, err := themeInt(v, path)
+	if err != nil {
+		got := describeThemeValue(v)
+		if f, ok := themeNumber(v); ok {
+			got = fmt.Sprint(f)
+		}
+		return s, themeErrorf(path, "expected non-negative integer or -1, got %s", got)
+	}
+	return s.TabWidth(n), nil
+}
+
+func themeColorProperty(set func(lipgloss.Style, lipgloss.TerminalColor) lipgloss.Style) themeApplyFunc {
+	return func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		c, err := themeColor(v, path)
+		if err != nil {
+			return s, err
+		}
+		return set(s, c), nil
+	}
+}
+
+// themeProperties lists the supported style properties in the order they
+// are applied, so shorthands like "padding" come before "padding_top"
+var themeProperties = []struct {
+	name  string
+	apply themeApplyFunc
+}{
+	{"foreground", themeColorProperty(lipgloss.Style.Foreground)},
+	{"background", themeColorProperty(lipgloss.Style.Background)},
+	{"bold", themeBoolProperty(lipgloss.Style.Bold)},
+	{"italic", themeBoolProperty(lipgloss.Style.Italic)},
+	{"underline", themeBoolProperty(lipgloss.Style.Underline)},
+	{"strikethrough", themeBoolProperty(lipgloss.Style.Strikethrough)},
+	{"reverse", themeBoolProperty(lipgloss.Style.Reverse)},
+	{"blink", themeBoolProperty(lipgloss.Style.Blink)},
+	{"faint", themeBoolProperty(lipgloss.Style.Faint)},
+	{"underline_spaces", themeBoolProperty(lipgloss.Style.UnderlineSpaces)},
+	{"strikethrough_spaces", themeBoolProperty(lipgloss.Style.StrikethroughSpaces)},
+	{"color_whitespace", themeBoolProperty(lipgloss.Style.ColorWhitespace)},
+	{"inline", themeBoolProperty(lipgloss.Style.Inline)},
+	{"width", themeIntProperty(lipgloss.Style.Width)},
+	{"height", themeIntProperty(lipgloss.Style.Height)},
+	{"max_width", themeIntProperty(lipgloss.Style.MaxWidth)},
+	{"max_height", themeIntProperty(lipgloss.Style.MaxHeight)},
+	{"tab_width", applyThemeTabWidth},
+	{"align", func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		pos, err := themePosition(v, path, themeHorizontal)
+		return s.AlignHorizontal(pos), err
+	}},
+	{"align_vertical", func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		pos, err := themePosition(v, path, themeVertical)
+		return s.AlignVertical(pos), err
+	}},
+	{"padding", func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		sides, err := themeSides(v, path)
+		return s.Padding(sides...), err
+	}},
+	{"padding_top", themeIntProperty(lipgloss.Style.PaddingTop)},
+	{"padding_right", themeIntProperty(lipgloss.Style.PaddingRight)},
+	{"padding_bottom", themeIntProperty(lipgloss.Style.PaddingBottom)},
+	{"padding_left", themeIntProperty(lipgloss.Style.PaddingLeft)},
+	{"margin", func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		sides, err := themeSides(v, path)
+		return s.Margin(sides...), err
+	}},
+	{"margin_top", themeIntProperty(lipgloss.Style.MarginTop)},
+	{"margin_right", themeIntProperty(lipgloss.Style.MarginRight)},
+	{"margin_bottom", themeIntProperty(lipgloss.Style.MarginBottom)},
+	{"margin_left", themeIntProperty(lipgloss.Style.MarginLeft)},
+	{"margin_background", themeColorProperty(lipgloss.Style.MarginBackground)},
+	{"border", applyThemeBorder},
+	{"border_foreground", themeColorProperty(func(s lipgloss.Style, c lipgloss.TerminalColor) lipgloss.Style {
+		return s.BorderForeground(c)
+	})},
+	{"border_background", themeColorProperty(func(s lipgloss.Style, c lipgloss.TerminalColor) lipgloss.Style {
+		return s.BorderBackground(c)
+	})},
+	{"border_top_foreground", themeColorProperty(lipgloss.Style.BorderTopForeground)},
+	{"border_right_foreground", themeColorProperty(lipgloss.Style.BorderRightForeground)},
+	{"border_bottom_foreground", themeColorProperty(lipgloss.Style.BorderBottomForeground)},
+	{"border_left_foreground", themeColorProperty(lipgloss.Style.BorderLeftForeground)},
+	{"border_top_background", themeColorProperty(lipgloss.Style.BorderTopBackground)},
+	{"border_right_background", themeColorProperty(lipgloss.Style.BorderRightBackground)},
+	{"border_bottom_background", themeColorProperty(lipgloss.Style.BorderBottomBackground)},
+	{"border_left_background", themeColorProperty(lipgloss.Style.BorderLeftBackground)},
+	{"value", func(s lipgloss.Style, v interface{}, path string) (lipgloss.Style, error) {
+		str, err := themeString(v, path)
+		return s.SetString(str), err
+	}},
+}
+
+// applyThemeEntry applies the properties of a style entry on top of style.
+// Keys listed in ignore are handled by the caller.
+func applyThemeEntry(style lipgloss.Style, entry map[string]interface{}, path string, ignore ...string) (lipgloss.Style, error) {
+	for _, key := range sortedKeys(entry) {
+		known := false
+		for _, name := range ignore {
+			known = known || name == key
+		}
+		for _, p := range themeProperties {
+			known = known || p.name == key
+		}
+		if !known {
+			return style, themeErrorf(joinThemePath(path, key), "unknown style property")
+		}
+	}
+
+	for _, p := range themeProperties {
+		v, ok := entry[p.name]
+		if !ok {
+			continue
+		}
+		var err error
+		if style, err = p.apply(style, v, joinThemePath(path, p.name)); err != nil {
+			return style, err
+		}
+	}
+	return style, nil
+}
+
+func joinThemePath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// themeBuilder resolves style entries, following inheritance
+type themeBuilder struct {
+	entries  map[string]map[string]interface{}
+	resolved map[string]lipgloss.Style
+	visiting []string
+}
+
+func (b *themeBuilder) build(name string) (lipgloss.Style, error) {
+	if s, ok := b.resolved[name]; ok {
+		return s, nil
+	}
+	path := "styles." + name
+	for i, v := range b.visiting {
+		if v == name {
+			cycle := append(append([]string{}, b.visiting[i:]...), name)
+			return lipgloss.Style{}, themeErrorf(path+".inherit", "inheritance cycle %s", strings.Join(cycle, " -> "))
+		}
+	}
+	b.visiting = append(b.visiting, name)
+	defer func() { b.visiting = b.visiting[:len(b.visiting)-1] }()
+
+	entry := b.entries[name]
+	style := lipgloss.NewStyle()
+	if parents, ok := entry["inherit"]; ok {
+		var err error
+		if style, err = b.inherit(parents, path+".inherit"); err != nil {
+			return lipgloss.Style{}, err
+		}
+	}
+
+	style, err := applyThemeEntry(style, entry, path, "inherit")
+	if err != nil {
+		return lipgloss.Style{}, err
+	}
+
+	b.resolved[name] = style
+	return style, nil
+}
+
+// inherit starts from a copy of the first parent; later parents only fill
+// in properties that are still unset, following Style.Inherit
+func (b *themeBuilder) inherit(v interface{}, path string) (lipgloss.Style, error) {
+	var names []string
+	var paths []string
+	if list, ok := v.([]interface{}); ok {
+		for i, item := range list {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			name, err := themeString(item, itemPath)
+			if err != nil {
+				return lipgloss.Style{}, err
+			}
+			names = append(names, name)
+			paths = append(paths, itemPath)
+		}
+	} else {
+		name, err := themeString(v, path)
+		if err != nil {
+			return lipgloss.Style{}, err
+		}
+		names, paths = []string{name}, []string{path}
+	}
+
+	style := lipgloss.NewStyle()
+	for i, name := range names {
+		if _, ok := b.entries[name]; !ok {
+			return lipgloss.Style{}, themeErrorf(paths[i], "unknown style %q", name)
+		}
+		parent, err := b.build(name)
+		if err != nil {
+			return lipgloss.Style{}, err
+		}
+		if i == 0 {
+			style = parent
+		} else {
+			style = style.Inherit(parent)
+		}
+	}
+	return style, nil
+}
+
+// loadTheme parses a theme document and registers its styles in a new theme
+func loadTheme(source []byte, format int) (uint64, error) {
+	root, err := decodeThemeDocument(source, format)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range sortedKeys(root) {
+		if key != "styles" {
+			return 0, themeErrorf(key, "unknown top-level key (expected \"styles\")")
+		}
+	}
+	rawStyles, ok := root["styles"]
+	if !ok {
+		return 0, themeErrorf("styles", "missing")
+	}
+	styles, ok := themeMap(rawStyles)
+	if !ok {
+		return 0, themeErrorf("styles", "expected object, got %s", describeThemeValue(rawStyles))
+	}
+
+	b := &themeBuilder{
+		entries:  make(map[string]map[string]interface{}, len(styles)),
+		resolved: make(map[string]lipgloss.Style, len(styles)),
+	}
+	for name, raw := range styles {
+		entry, ok := themeMap(raw)
+		if !ok {
+			return 0, themeErrorf("styles."+name, "expected object, got %s", describeThemeValue(raw))
+		}
+		b.entries[name] = entry
+	}
+
+	names := sortedKeys(styles)
+	for _, name := range names {
+		if _, err := b.build(name); err != nil {
+			return 0, err
+		}
+	}
+
+	t := newTheme()
+	for _, name := range names {
+		t.Set(name, b.resolved[name])
+	}
+	return themeReg.Register(t), nil
+}
+
+// setErrorOut hands err to the caller through errOut, if provided
+func setErrorOut(errOut **C.char, err error) {
+	if errOut == nil {
+		return
+	}
+	cs, cerr := String.CString(err.Error())
+	if cerr != nil {
+		*errOut = nil
+		return
+	}
+	Memory.Track(unsafe.Pointer(cs), "theme error")
+	*errOut = cs
+}
+
+// LoadTheme parses a theme document and returns the ID of a new theme, or 0
+// on failure. When errOut is not NULL it receives a description of the
+// failure, including the path of the offending value, which must be
+// released with FreeString.
+//
+//export LoadTheme
+func LoadTheme(source *C.char, format C.int, errOut **C.char) C.uint64_t {
+	defer recoverPanic("LoadTheme")
+	if errOut != nil {
+		*errOut = nil
+	}
+	id, err := loadTheme([]byte(String.GoString(source)), int(format))
+	if err != nil {
+		Log(LogLevelError, "LoadTheme: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	return C.uint64_t(id)
+}
+
+// LoadThemeFile loads a theme from a file, picking the format from its
+// extension (.json, .toml, .yaml or .yml). Errors are reported as in
+// LoadTheme and are prefixed with the file name.
+//
+//export LoadThemeFile
+func LoadThemeFile(path *C.char, errOut **C.char) C.uint64_t {
+	defer recoverPanic("LoadThemeFile")
+	if errOut != nil {
+		*errOut = nil
+	}
+	goPath := String.GoString(path)
+	source, err := os.ReadFile(goPath)
+	if err != nil {
+		Log(LogLevelError, "LoadThemeFile: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	id, err := loadTheme(source, themeFormatForPath(goPath))
+	if err != nil {
+		err = fmt.Errorf("%s: %w", goPath, err)
+		Log(LogLevelError, "LoadThemeFile: %v", err)
+		setErrorOut(errOut, err)
+		return 0
+	}
+	return C.uint64_t(id)
+}
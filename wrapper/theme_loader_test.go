@@ -0,0 +1,147 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const themeJSON = `{
+  "styles": {
+    "base":  { "foreground": "#eeeeee", "padding": [0, 1] },
+    "title": { "inherit": "base", "bold": true }
+  }
+}`
+
+// loadTheme loads a theme document and frees it when the test ends
+func loadTestTheme(t *testing.T, source string, format int) cHandle {
+	t.Helper()
+	var errOut *cChar
+	id := LoadTheme(cString(t, source), cInt(format), &errOut)
+	if id == 0 {
+		t.Fatalf("LoadTheme failed: %s", takeString(errOut))
+	}
+	t.Cleanup(func() { FreeTheme(id) })
+	return id
+}
+
+func TestLoadTheme(t *testing.T) {
+	title := lipgloss.NewStyle().Foreground(lipgloss.Color("#eeeeee")).Padding(0, 1).Bold(true)
+	for _, tc := range []struct {
+		format int
+		source string
+	}{
+		{0, themeJSON},
+		{1, themeJSON},
+		{2, "[styles.base]\nforeground = \"#eeeeee\"\npadding = [0, 1]\n\n[styles.title]\ninherit = \"base\"\nbold = true\n"},
+		{3, "styles:\n  base: {foreground: \"#eeeeee\", padding: [0, 1]}\n  title: {inherit: base, bold: true}\n"},
+	} {
+		theme := loadTestTheme(t, tc.source, tc.format)
+		id := ThemeGetStyle(theme, cString(t, "title"))
+		if got := render(t, id, "x"); got != title.Render("x") {
+			t.Errorf("format %d: title renders %q, want %q", tc.format, got, title.Render("x"))
+		}
+	}
+}
+
+func TestLoadThemeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		source string
+		want   string
+	}{
+		{`{"styles": {"title": {"padding": [0, "x"]}}}`, "styles.title.padding[1]"},
+		{`{"styles": {"a": {"inherit": "b"}, "b": {"inherit": "a"}}}`, "inherit"},
+		{`{"styles": [`, "JSON"},
+	} {
+		var errOut *cChar
+		expectLog(t, "LoadTheme", func() {
+			if got := LoadTheme(cString(t, tc.source), 1, &errOut); got != 0 {
+				FreeTheme(got)
+				t.Errorf("LoadTheme(%s) succeeded", tc.source)
+			}
+		})
+		if msg := takeString(errOut); !strings.Contains(msg, tc.want) {
+			t.Errorf("LoadTheme(%s) error = %q, want it to mention %q", tc.source, msg, tc.want)
+		}
+	}
+
+	// errOut is optional
+	expectLog(t, "LoadTheme", func() { LoadTheme(cString(t, "{"), 1, nil) })
+}
+
+func TestLoadThemeFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "theme.json")
+	if err := os.WriteFile(path, []byte(themeJSON), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var errOut *cChar
+	theme := LoadThemeFile(cString(t, path), &errOut)
+	if theme == 0 {
+		t.Fatalf("LoadThemeFile failed: %s", takeString(errOut))
+	}
+	defer FreeTheme(theme)
+	if ThemeGetStyle(theme, cString(t, "base")) == 0 {
+		t.Error("LoadThemeFile lost the base style")
+	}
+
+	expectLog(t, "LoadThemeFile", func() {
+		if got := LoadThemeFile(cString(t, filepath.Join(dir, "missing.json")), &errOut); got != 0 {
+			t.Error("LoadThemeFile of a missing file succeeded")
+		}
+	})
+	if msg := takeString(errOut); !strings.Contains(msg, "missing.json") {
+		t.Errorf("LoadThemeFile error = %q", msg)
+	}
+}
+
+func TestThemeRegistry(t *testing.T) {
+	theme := NewTheme()
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+
+	if ThemeSetStyle(theme, cString(t, "title"), bold) != 1 {
+		t.Fatal("ThemeSetStyle failed")
+	}
+	owned := ThemeGetStyle(theme, cString(t, "title"))
+	if owned == 0 || owned == bold {
+		t.Fatalf("ThemeGetStyle = %#x, want a copy of %#x", owned, bold)
+	}
+	if StyleGetBold(owned) != 1 {
+		t.Error("the theme's copy is not bold")
+	}
+	if ThemeGetStyle(theme, cString(t, "missing")) != 0 {
+		t.Error("ThemeGetStyle of a missing name returned a style")
+	}
+
+	expectLog(t, "empty style name", func() {
+		if ThemeSetStyle(theme, cString(t, ""), bold) != 0 {
+			t.Error("ThemeSetStyle accepted an empty name")
+		}
+	})
+	expectLog(t, "ThemeSetStyle style error", func() {
+		if ThemeSetStyle(theme, cString(t, "x"), stale()) != 0 {
+			t.Error("ThemeSetStyle accepted a freed style")
+		}
+	})
+
+	FreeTheme(theme)
+	if HandleIsValid(owned) != 0 {
+		t.Error("FreeTheme did not free the styles it owns")
+	}
+	expectLog(t, "theme not found", func() {
+		if ThemeSetStyle(theme, cString(t, "title"), bold) != 0 {
+			t.Error("ThemeSetStyle on a freed theme succeeded")
+		}
+	})
+	expectLog(t, "theme not found", func() {
+		if ThemeGetStyle(theme, cString(t, "title")) != 0 {
+			t.Error("ThemeGetStyle on a freed theme returned a style")
+		}
+	})
+}
@@ -0,0 +1,131 @@
+package main
+
+/*
+#include <stdlib.h>
+#include <stdint.h>
+#include "lipgloss_types.h"
+*/
+import "C"
+import (
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// theme maps element names to styles. Each style is a copy registered in
+// styleReg and owned by the theme, so it stays valid until FreeTheme even if
+// the caller frees the style it was created from.
+type theme struct {
+	sync.RWMutex
+	styles map[string]uint64
+}
+
+// Set stores a copy of style under name, releasing any style it replaces
+func (t *theme) Set(name string, style lipgloss.Style) uint64 {
+	id := styleReg.Register(&style)
+	scopes.escapeHandle(id) // owned by the theme, not the caller's scope
+
+	t.Lock()
+	defer t.Unlock()
+	if old, ok := t.styles[name]; ok {
+		styleReg.Remove(old)
+	}
+	t.styles[name] = id
+	return id
+}
+
+// ID returns the style ID registered for name, or 0
+func (t *theme) ID(name string) uint64 {
+	t.RLock()
+	defer t.RUnlock()
+	return t.styles[name]
+}
+
+// Style looks up the style registered for name
+func (t *theme) Style(name string) (lipgloss.Style, bool) {
+	id := t.ID(name)
+	if id == 0 {
+		return lipgloss.Style{}, false
+	}
+	style, err := getStyleSafe(id, "theme-style")
+	if err != nil {
+		return lipgloss.Style{}, false
+	}
+	return *style, true
+}
+
+// release frees every style owned by the theme
+func (t *theme) release() {
+	t.Lock()
+	defer t.Unlock()
+	for name, id := range t.styles {
+		styleReg.Remove(id)
+		delete(t.styles, name)
+	}
+}
+
+// themeRegistry manages theme instances with thread safety
+type themeRegistry struct {
+	handleRegistry[theme]
+}
+
+var themeReg = &themeRegistry{handleRegistry[theme]{kind: handleKindTheme}}
+
+// Remove frees a theme handle along with the styles the theme owns
+func (r *themeRegistry) Remove(id uint64) {
+	if t := r.handleRegistry.Remove(id); t != nil {
+		t.release()
+	}
+}
+
+func newTheme() *theme {
+	return &theme{styles: make(map[string]uint64)}
+}
+
+//export NewTheme
+func NewTheme() C.uint64_t {
+	defer recoverPanic("NewTheme")
+	return C.uint64_t(themeReg.Register(newTheme()))
+}
+
+//export ThemeSetStyle
+func ThemeSetStyle(themeID C.uint64_t, name *C.char, styleID C.uint64_t) C.int {
+	defer recoverPanic("ThemeSetStyle")
+	t := themeReg.Get(uint64(themeID))
+	if t == nil {
+		Log(LogLevelError, "ThemeSetStyle: theme not found with ID: %d", uint64(themeID))
+		return 0
+	}
+	goName := String.GoString(name)
+	if goName == "" {
+		Log(LogLevelError, "ThemeSetStyle: empty style name")
+		return 0
+	}
+	style, err := Style.SafeGet(uint64(styleID), "theme-set-style")
+	if err != nil {
+		Log(LogLevelError, "ThemeSetStyle style error: %v", err)
+		return 0
+	}
+	t.Set(goName, *style)
+	return 1
+}
+
+// ThemeGetStyle returns the style ID registered under name. The style is
+// owned by the theme and must not be freed by the caller.
+//
+//export ThemeGetStyle
+func ThemeGetStyle(themeID C.uint64_t, name *C.char) C.uint64_t {
+	defer recoverPanic("ThemeGetStyle")
+	t := themeReg.Get(uint64(themeID))
+	if t == nil {
+		Log(LogLevelError, "ThemeGetStyle: theme not found with ID: %d", uint64(themeID))
+		return 0
+	}
+	return C.uint64_t(t.ID(String.GoString(name)))
+}
+
+//export FreeTheme
+func FreeTheme(themeID C.uint64_t) {
+	defer recoverPanic("FreeTheme")
+	themeReg.Remove(uint64(themeID))
+}
@@ -4,133 +4,444 @@ package main
 #include <stdlib.h>
 #include <stdint.h>
 #include "lipgloss_types.h"
+
+static inline const char* callTreeEnumerator(CTreeEnumeratorFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
+
+static inline const char* callTreeIndenter(CTreeIndenterFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
 */
 import "C"
 import (
-	"sync"
-	"sync/atomic"
+	"unsafe"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/lipgloss/tree"
 )
 
-// treeRegistry manages tree instances with thread safety
-type treeRegistry struct {
-	sync.RWMutex
-	nextID uint64
-	trees  map[uint64]*tree.Tree
+// treeNode is the wrapper-side model of a tree. lipgloss trees can only be
+// appended to, so the registry keeps this model and builds a fresh
+// tree.Tree from it on every render. That lets C callers remove, replace
+// and hide children after insertion.
+type treeNode struct {
+	root            string
+	hidden          bool
+	children        []treeChild
+	enumerator      tree.Enumerator
+	indenter        tree.Indenter
+	rootStyle       *lipgloss.Style
+	itemStyle       tree.StyleFunc
+	enumeratorStyle tree.StyleFunc
 }
 
-var treeReg = &treeRegistry{
-	trees: make(map[uint64]*tree.Tree),
+// treeChild is either a string leaf or a nested tree
+type treeChild struct {
+	value  string
+	hidden bool
+	tree   *treeNode
 }
 
-// Register adds a tree to the registry and returns its ID
-func (r *treeRegistry) Register(t *tree.Tree) uint64 {
-	if t == nil {
-		return 0
+// leafNode is a tree.Node for string children that can be hidden
+type leafNode struct {
+	value  string
+	hidden bool
+}
+
+func (l *leafNode) Value() string           { return l.value }
+func (l *leafNode) Hidden() bool            { return l.hidden }
+func (l *leafNode) Children() tree.Children { return tree.NodeChildren(nil) }
+func (l *leafNode) String() string          { return l.value }
+
+// build converts the model into a lipgloss tree. Renderer settings are only
+// applied when set so that nested trees keep inheriting their parent's.
+func (n *treeNode) build() *tree.Tree {
+	t := tree.Root(n.root)
+	t.Hide(n.hidden)
+
+	if n.enumerator != nil {
+		t.Enumerator(n.enumerator)
+	}
+	if n.indenter != nil {
+		t.Indenter(n.indenter)
+	}
+	if n.rootStyle != nil {
+		t.RootStyle(*n.rootStyle)
+	}
+	if n.itemStyle != nil {
+		t.ItemStyleFunc(n.itemStyle)
+	}
+	if n.enumeratorStyle != nil {
+		t.EnumeratorStyleFunc(n.enumeratorStyle)
+	}
+
+	for _, c := range n.children {
+		if c.tree == nil {
+			t.Child(&leafNode{value: c.value, hidden: c.hidden})
+			continue
+		}
+		sub := c.tree.build()
+		if c.hidden {
+			sub.Hide(true)
+		}
+		t.Child(sub)
 	}
-	r.Lock()
-	defer r.Unlock()
+	return t
+}
 
-	id := atomic.AddUint64(&r.nextID, 1)
-	r.trees[id] = t
-	return id
+// contains reports whether other is n or one of its descendants
+func (n *treeNode) contains(other *treeNode) bool {
+	if n == other {
+		return true
+	}
+	for _, c := range n.children {
+		if c.tree != nil && c.tree.contains(other) {
+			return true
+		}
+	}
+	return false
 }
 
-// Get retrieves a tree from the registry
-func (r *treeRegistry) Get(id uint64) *tree.Tree {
-	r.RLock()
-	defer r.RUnlock()
-	return r.trees[id]
+// validIndex reports whether index addresses an existing child
+func (n *treeNode) validIndex(index int) bool {
+	return index >= 0 && index < len(n.children)
 }
 
-// Remove deletes a tree from the registry
-func (r *treeRegistry) Remove(id uint64) {
-	r.Lock()
-	defer r.Unlock()
-	delete(r.trees, id)
+// treeRegistry manages tree instances with thread safety
+type treeRegistry struct {
+	handleRegistry[treeNode]
 }
 
+var treeReg = &treeRegistry{handleRegistry[treeNode]{kind: handleKindTree}}
+
 //export NewTree
 func NewTree() C.uint64_t {
-	t := tree.New()
-	return C.uint64_t(treeReg.Register(t))
+	defer recoverPanic("NewTree")
+	return C.uint64_t(treeReg.Register(&treeNode{}))
+}
+
+//export TreeSetRoot
+func TreeSetRoot(id C.uint64_t, root *C.char) {
+	defer recoverPanic("TreeSetRoot")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	t.root = C.GoString(root)
 }
 
 //export TreeAddChildValue
 func TreeAddChildValue(parentID C.uint64_t, value *C.char) {
+	defer recoverPanic("TreeAddChildValue")
 	parent := treeReg.Get(uint64(parentID))
 	if parent == nil {
 		return
 	}
-	parent.Child(C.GoString(value))
+	parent.children = append(parent.children, treeChild{value: C.GoString(value)})
 }
 
 //export TreeAddChildTree
 func TreeAddChildTree(parentID C.uint64_t, childID C.uint64_t) {
+	defer recoverPanic("TreeAddChildTree")
 	parent := treeReg.Get(uint64(parentID))
 	child := treeReg.Get(uint64(childID))
 	if parent == nil || child == nil {
 		return
 	}
-	parent.Child(child)
+	if child.contains(parent) {
+		Log(LogLevelError, "TreeAddChildTree: tree %d already contains tree %d", uint64(childID), uint64(parentID))
+		return
+	}
+	parent.children = append(parent.children, treeChild{tree: child})
+}
+
+//export TreeChildCount
+func TreeChildCount(id C.uint64_t) C.int {
+	defer recoverPanic("TreeChildCount")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return 0
+	}
+	return C.int(len(t.children))
+}
+
+//export TreeRemoveChild
+func TreeRemoveChild(id C.uint64_t, index C.int) C.int {
+	defer recoverPanic("TreeRemoveChild")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return 0
+	}
+	if !t.validIndex(int(index)) {
+		Log(LogLevelError, "TreeRemoveChild: index %d out of range for tree %d", int(index), uint64(id))
+		return 0
+	}
+	t.children = append(t.children[:index], t.children[index+1:]...)
+	return 1
+}
+
+//export TreeReplaceChildValue
+func TreeReplaceChildValue(id C.uint64_t, index C.int, value *C.char) C.int {
+	defer recoverPanic("TreeReplaceChildValue")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return 0
+	}
+	if !t.validIndex(int(index)) {
+		Log(LogLevelError, "TreeReplaceChildValue: index %d out of range for tree %d", int(index), uint64(id))
+		return 0
+	}
+	t.children[index] = treeChild{value: C.GoString(value)}
+	return 1
+}
+
+//export TreeReplaceChildTree
+func TreeReplaceChildTree(id C.uint64_t, index C.int, childID C.uint64_t) C.int {
+	defer recoverPanic("TreeReplaceChildTree")
+	t := treeReg.Get(uint64(id))
+	child := treeReg.Get(uint64(childID))
+	if t == nil || child == nil {
+		return 0
+	}
+	if !t.validIndex(int(index)) {
+		Log(LogLevelError, "TreeReplaceChildTree: index %d out of range for tree %d", int(index), uint64(id))
+		return 0
+	}
+	if child.contains(t) {
+		Log(LogLevelError, "TreeReplaceChildTree: tree %d already contains tree %d", uint64(childID), uint64(id))
+		return 0
+	}
+	t.children[index] = treeChild{tree: child}
+	return 1
+}
+
+//export TreeSetHidden
+func TreeSetHidden(id C.uint64_t, hide C.int) {
+	defer recoverPanic("TreeSetHidden")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	t.hidden = String.ToBool(hide)
+}
+
+//export TreeSetChildHidden
+func TreeSetChildHidden(id C.uint64_t, index C.int, hide C.int) C.int {
+	defer recoverPanic("TreeSetChildHidden")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return 0
+	}
+	if !t.validIndex(int(index)) {
+		Log(LogLevelError, "TreeSetChildHidden: index %d out of range for tree %d", int(index), uint64(id))
+		return 0
+	}
+	t.children[index].hidden = String.ToBool(hide)
+	return 1
+}
+
+// asciiEnumerator draws branches without box-drawing glyphs
+//
+// |-- Foo
+// `-- Bar
+func asciiEnumerator(children tree.Children, index int) string {
+	if children.Length()-1 == index {
+		return "`--"
+	}
+	return "|--"
+}
+
+// asciiIndenter is the indenter matching asciiEnumerator
+func asciiIndenter(children tree.Children, index int) string {
+	if children.Length()-1 == index {
+		return "   "
+	}
+	return "|  "
+}
+
+// blankIndenter indents nested trees without any connecting glyph
+func blankIndenter(children tree.Children, index int) string {
+	return "   "
 }
 
 //export TreeSetEnumerator
 func TreeSetEnumerator(id C.uint64_t, enumType C.int) {
+	defer recoverPanic("TreeSetEnumerator")
 	t := treeReg.Get(uint64(id))
 	if t == nil {
 		return
 	}
 	switch enumType {
-	case 0:
-		t.Enumerator(tree.DefaultEnumerator)
-	case 1:
-		t.Enumerator(tree.RoundedEnumerator)
+	case C.TREE_ENUM_DEFAULT:
+		t.enumerator = tree.DefaultEnumerator
+	case C.TREE_ENUM_ROUNDED:
+		t.enumerator = tree.RoundedEnumerator
+	case C.TREE_ENUM_ASCII:
+		t.enumerator = asciiEnumerator
+	default:
+		Log(LogLevelError, "TreeSetEnumerator: unknown enumerator type %d", int(enumType))
+	}
+}
+
+//export TreeSetEnumeratorFunc
+func TreeSetEnumeratorFunc(id C.uint64_t, fn C.CTreeEnumeratorFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("TreeSetEnumeratorFunc")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "TreeSetEnumeratorFunc received nil callback")
+		return
+	}
+	t.enumerator = func(children tree.Children, index int) string {
+		return C.GoString(C.callTreeEnumerator(fn, C.int(children.Length()), C.int(index), userdata))
 	}
 }
 
 //export TreeSetIndenter
 func TreeSetIndenter(id C.uint64_t, indentType C.int) {
+	defer recoverPanic("TreeSetIndenter")
 	t := treeReg.Get(uint64(id))
 	if t == nil {
 		return
 	}
 	switch indentType {
-	case 0:
-		t.Indenter(tree.DefaultIndenter)
-	case 1:
-		t.Indenter(func(children tree.Children, index int) string {
+	case C.TREE_INDENT_DEFAULT:
+		t.indenter = tree.DefaultIndenter
+	case C.TREE_INDENT_SPACES:
+		t.indenter = func(children tree.Children, index int) string {
 			return "    "
-		})
+		}
+	case C.TREE_INDENT_ASCII:
+		t.indenter = asciiIndenter
+	case C.TREE_INDENT_BLANK:
+		t.indenter = blankIndenter
+	default:
+		Log(LogLevelError, "TreeSetIndenter: unknown indenter type %d", int(indentType))
+	}
+}
+
+//export TreeSetIndenterFunc
+func TreeSetIndenterFunc(id C.uint64_t, fn C.CTreeIndenterFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("TreeSetIndenterFunc")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "TreeSetIndenterFunc received nil callback")
+		return
+	}
+	t.indenter = func(children tree.Children, index int) string {
+		return C.GoString(C.callTreeIndenter(fn, C.int(children.Length()), C.int(index), userdata))
+	}
+}
+
+// staticTreeStyle returns a tree.StyleFunc applying style to every node
+func staticTreeStyle(style lipgloss.Style) tree.StyleFunc {
+	return func(tree.Children, int) lipgloss.Style { return style }
+}
+
+// callbackTreeStyle adapts a C style callback into a tree.StyleFunc
+func callbackTreeStyle(fn C.CStyleFunc, userdata unsafe.Pointer, op string) tree.StyleFunc {
+	styleFunc := Style.Func(fn, userdata, op)
+	return func(children tree.Children, index int) lipgloss.Style {
+		return styleFunc(children.Length(), index)
 	}
 }
 
 //export TreeSetItemStyle
-func TreeSetItemStyle(id C.uint64_t, style *C.char) {
+func TreeSetItemStyle(id C.uint64_t, styleID C.uint64_t) {
+	defer recoverPanic("TreeSetItemStyle")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	style, err := Style.SafeGet(uint64(styleID), "tree-item-style")
+	if err != nil {
+		Log(LogLevelError, "TreeSetItemStyle style error: %v", err)
+		return
+	}
+	t.itemStyle = staticTreeStyle(*style)
+}
+
+//export TreeSetItemStyleFunc
+func TreeSetItemStyleFunc(id C.uint64_t, fn C.CStyleFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("TreeSetItemStyleFunc")
 	t := treeReg.Get(uint64(id))
 	if t == nil {
 		return
 	}
-	styled := lipgloss.NewStyle().Foreground(lipgloss.Color(C.GoString(style)))
-	t.ItemStyle(styled)
+	if fn == nil {
+		Log(LogLevelError, "TreeSetItemStyleFunc received nil callback")
+		return
+	}
+	t.itemStyle = callbackTreeStyle(fn, userdata, "tree-item-style-func")
+}
+
+//export TreeSetRootStyle
+func TreeSetRootStyle(id C.uint64_t, styleID C.uint64_t) {
+	defer recoverPanic("TreeSetRootStyle")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	style, err := Style.SafeGet(uint64(styleID), "tree-root-style")
+	if err != nil {
+		Log(LogLevelError, "TreeSetRootStyle style error: %v", err)
+		return
+	}
+	styled := *style
+	t.rootStyle = &styled
+}
+
+//export TreeSetEnumeratorStyle
+func TreeSetEnumeratorStyle(id C.uint64_t, styleID C.uint64_t) {
+	defer recoverPanic("TreeSetEnumeratorStyle")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	style, err := Style.SafeGet(uint64(styleID), "tree-enumerator-style")
+	if err != nil {
+		Log(LogLevelError, "TreeSetEnumeratorStyle style error: %v", err)
+		return
+	}
+	t.enumeratorStyle = staticTreeStyle(*style)
+}
+
+//export TreeSetEnumeratorStyleFunc
+func TreeSetEnumeratorStyleFunc(id C.uint64_t, fn C.CStyleFunc, userdata unsafe.Pointer) {
+	defer recoverPanic("TreeSetEnumeratorStyleFunc")
+	t := treeReg.Get(uint64(id))
+	if t == nil {
+		return
+	}
+	if fn == nil {
+		Log(LogLevelError, "TreeSetEnumeratorStyleFunc received nil callback")
+		return
+	}
+	t.enumeratorStyle = callbackTreeStyle(fn, userdata, "tree-enumerator-style-func")
 }
 
 //export RenderTree
-func RenderTree(id C.uint64_t) *C.char {
+func RenderTree(id C.uint64_t) (ret *C.char) {
+	defer recoverString("RenderTree", &ret, "(empty tree)")
 	t := treeReg.Get(uint64(id))
 	if t == nil {
-		return C.CString("(empty tree)")
+		return Memory.CString("(empty tree)", "RenderTree result")
 	}
-	result := t.String()
+	result := t.build().String()
 	if result == "" {
 		result = "(empty tree)"
 	}
-	return C.CString(result)
+	return Memory.CString(result, "RenderTree result")
 }
 
 //export FreeTree
 func FreeTree(id C.uint64_t) {
+	defer recoverPanic("FreeTree")
 	treeReg.Remove(uint64(id))
 }
@@ -0,0 +1,204 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// newTestTree returns a tree freed when the test ends
+func newTestTree(t testing.TB, root string, children ...string) cHandle {
+	id := NewTree()
+	t.Cleanup(func() { FreeTree(id) })
+	if root != "" {
+		TreeSetRoot(id, cString(t, root))
+	}
+	for _, child := range children {
+		TreeAddChildValue(id, cString(t, child))
+	}
+	return id
+}
+
+// renderTree renders a tree and frees the result
+func renderTree(id cHandle) string {
+	return takeString(RenderTree(id))
+}
+
+func TestRenderTree(t *testing.T) {
+	if got := renderTree(newTestTree(t, "")); got != "(empty tree)" {
+		t.Errorf("an empty tree renders %q", got)
+	}
+
+	root := newTestTree(t, "Fruit", "Apple", "Banana")
+	nested := newTestTree(t, "Citrus", "Lemon", "Lime")
+	TreeAddChildTree(root, nested)
+	TreeAddChildValue(root, cString(t, "Cherry"))
+	if got := TreeChildCount(root); got != 4 {
+		t.Errorf("TreeChildCount = %d, want 4", got)
+	}
+	golden(t, "tree_default", renderTree(root))
+
+	TreeSetEnumerator(root, treeEnumRounded)
+	TreeSetEnumerator(nested, treeEnumRounded)
+	if got := renderTree(root); !strings.Contains(got, "╰── Cherry") {
+		t.Errorf("the rounded enumerator renders\n%s", got)
+	}
+
+	expectLog(t, "unknown enumerator type 9", func() { TreeSetEnumerator(root, 9) })
+	expectLog(t, "unknown indenter type 9", func() { TreeSetIndenter(root, 9) })
+}
+
+func TestTreeEnumerators(t *testing.T) {
+	root := newTestTree(t, "Fruit", "Apple", "Banana")
+	nested := newTestTree(t, "Citrus", "Lemon", "Lime")
+	TreeAddChildTree(root, nested)
+	TreeAddChildValue(root, cString(t, "Cherry"))
+
+	TreeSetEnumerator(root, treeEnumASCII)
+	TreeSetEnumerator(nested, treeEnumASCII)
+	TreeSetIndenter(root, treeIndentASCII)
+	golden(t, "tree_ascii", renderTree(root))
+
+	TreeSetIndenter(root, treeIndentSpaces)
+	if got := renderTree(root); !strings.Contains(got, "\n     |-- Lemon") {
+		t.Errorf("the spaces indenter renders\n%s", got)
+	}
+	TreeSetIndenter(root, treeIndentBlank)
+	if got := renderTree(root); !strings.Contains(got, "\n    |-- Lemon") {
+		t.Errorf("the blank indenter renders\n%s", got)
+	}
+
+	// Callbacks receive the siblings count and the index of each child
+	TreeSetEnumeratorFunc(root, arrowTreeEnumerator(), nil)
+	TreeSetIndenterFunc(root, barTreeIndenter(), nil)
+	want := "Fruit\n-> Apple\n-> Banana\n-> Citrus\n|   |-- Lemon\n|   `-- Lime\n=> Cherry"
+	if got := renderTree(root); got != want {
+		t.Errorf("with callbacks the tree renders %q, want %q", got, want)
+	}
+
+	expectLog(t, "TreeSetEnumeratorFunc received nil callback", func() { TreeSetEnumeratorFunc(root, nil, nil) })
+	expectLog(t, "TreeSetIndenterFunc received nil callback", func() { TreeSetIndenterFunc(root, nil, nil) })
+}
+
+func TestTreeEditing(t *testing.T) {
+	root := newTestTree(t, "Root", "a", "b", "c")
+	child := newTestTree(t, "Child", "x")
+
+	if TreeRemoveChild(root, 1) != 1 {
+		t.Fatal("TreeRemoveChild(1) failed")
+	}
+	if TreeReplaceChildValue(root, 0, cString(t, "A")) != 1 {
+		t.Fatal("TreeReplaceChildValue(0) failed")
+	}
+	if TreeReplaceChildTree(root, 1, child) != 1 {
+		t.Fatal("TreeReplaceChildTree(1) failed")
+	}
+	if got, want := renderTree(root), "Root\n├── A\n└── Child\n    └── x"; got != want {
+		t.Errorf("the edited tree renders %q, want %q", got, want)
+	}
+
+	if TreeSetChildHidden(root, 0, 1) != 1 {
+		t.Fatal("TreeSetChildHidden(0) failed")
+	}
+	if got, want := renderTree(root), "Root\n└── Child\n    └── x"; got != want {
+		t.Errorf("with a hidden child the tree renders %q, want %q", got, want)
+	}
+	TreeSetHidden(child, 1)
+	if got := renderTree(root); got != "Root" {
+		t.Errorf("with every child hidden the tree renders %q", got)
+	}
+
+	for name, fn := range map[string]func() cInt{
+		"TreeRemoveChild":       func() cInt { return TreeRemoveChild(root, 2) },
+		"TreeReplaceChildValue": func() cInt { return TreeReplaceChildValue(root, -1, cString(t, "x")) },
+		"TreeReplaceChildTree":  func() cInt { return TreeReplaceChildTree(root, 5, child) },
+		"TreeSetChildHidden":    func() cInt { return TreeSetChildHidden(root, 2, 1) },
+	} {
+		expectLog(t, name+": index", func() {
+			if got := fn(); got != 0 {
+				t.Errorf("%s with an index out of range = %d, want 0", name, got)
+			}
+		})
+	}
+
+	// A tree may not become its own descendant
+	expectLog(t, "already contains", func() { TreeAddChildTree(child, root) })
+	expectLog(t, "already contains", func() { TreeAddChildTree(root, root) })
+	expectLog(t, "already contains", func() {
+		if got := TreeReplaceChildTree(child, 0, root); got != 0 {
+			t.Errorf("TreeReplaceChildTree creating a cycle = %d, want 0", got)
+		}
+	})
+}
+
+func TestTreeStyles(t *testing.T) {
+	root := newTestTree(t, "Root", "a", "b", "c")
+	bold := keep(t, StyleBold(keep(t, NewStyle()), 1))
+	red := keep(t, StyleForeground(keep(t, NewStyle()), cString(t, "#ff0000")))
+
+	TreeSetRootStyle(root, bold)
+	TreeSetItemStyle(root, red)
+	TreeSetEnumeratorStyle(root, bold)
+	golden(t, "tree_styled", renderTree(root))
+
+	TreeSetItemStyleFunc(root, evenItemStyleFunc(), cHandlePtr(t, red))
+	TreeSetEnumeratorStyleFunc(root, evenItemStyleFunc(), cHandlePtr(t, bold))
+	TreeSetEnumeratorFunc(root, arrowTreeEnumerator(), nil)
+	TreeSetIndenterFunc(root, barTreeIndenter(), nil)
+	golden(t, "tree_callbacks", renderTree(root))
+
+	for name, fn := range map[string]func(){
+		"TreeSetRootStyle":       func() { TreeSetRootStyle(root, stale()) },
+		"TreeSetItemStyle":       func() { TreeSetItemStyle(root, stale()) },
+		"TreeSetEnumeratorStyle": func() { TreeSetEnumeratorStyle(root, stale()) },
+	} {
+		expectLog(t, name+" style error", fn)
+	}
+	for name, fn := range map[string]func(){
+		"TreeSetItemStyleFunc":       func() { TreeSetItemStyleFunc(root, nil, nil) },
+		"TreeSetEnumeratorStyleFunc": func() { TreeSetEnumeratorStyleFunc(root, nil, nil) },
+	} {
+		expectLog(t, name+" received nil callback", fn)
+	}
+}
+
+func TestTreeErrors(t *testing.T) {
+	id := NewTree()
+	FreeTree(id)
+	live := newTestTree(t, "live")
+	style := keep(t, NewStyle())
+
+	for name, fn := range map[string]func(){
+		"TreeSetRoot":                func() { TreeSetRoot(id, cString(t, "x")) },
+		"TreeAddChildValue":          func() { TreeAddChildValue(id, cString(t, "x")) },
+		"TreeAddChildTree":           func() { TreeAddChildTree(id, live) },
+		"TreeAddChildTree child":     func() { TreeAddChildTree(live, id) },
+		"TreeChildCount":             func() { TreeChildCount(id) },
+		"TreeRemoveChild":            func() { TreeRemoveChild(id, 0) },
+		"TreeReplaceChildValue":      func() { TreeReplaceChildValue(id, 0, cString(t, "x")) },
+		"TreeReplaceChildTree":       func() { TreeReplaceChildTree(id, 0, live) },
+		"TreeSetHidden":              func() { TreeSetHidden(id, 1) },
+		"TreeSetChildHidden":         func() { TreeSetChildHidden(id, 0, 1) },
+		"TreeSetEnumerator":          func() { TreeSetEnumerator(id, 0) },
+		"TreeSetEnumeratorFunc":      func() { TreeSetEnumeratorFunc(id, arrowTreeEnumerator(), nil) },
+		"TreeSetIndenter":            func() { TreeSetIndenter(id, 0) },
+		"TreeSetIndenterFunc":        func() { TreeSetIndenterFunc(id, barTreeIndenter(), nil) },
+		"TreeSetItemStyle":           func() { TreeSetItemStyle(id, style) },
+		"TreeSetItemStyleFunc":       func() { TreeSetItemStyleFunc(id, evenItemStyleFunc(), nil) },
+		"TreeSetRootStyle":           func() { TreeSetRootStyle(id, style) },
+		"TreeSetEnumeratorStyle":     func() { TreeSetEnumeratorStyle(id, style) },
+		"TreeSetEnumeratorStyleFunc": func() { TreeSetEnumeratorStyleFunc(id, evenItemStyleFunc(), nil) },
+		"FreeTree":                   func() { FreeTree(id) },
+	} {
+		if got := logged(fn); !strings.Contains(got, "tree handle") {
+			t.Errorf("%s on a freed tree logged %q", name, got)
+		}
+	}
+	if got := TreeChildCount(id); got != 0 {
+		t.Errorf("TreeChildCount of a freed tree = %d, want 0", got)
+	}
+	if got := renderTree(id); got != "(empty tree)" {
+		t.Errorf("RenderTree of a freed tree = %q", got)
+	}
+}
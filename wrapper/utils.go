@@ -4,12 +4,20 @@ package main
 #include <stdlib.h>
 #include <stdint.h>
 #include <stdbool.h>
+#include <string.h>
 #include "lipgloss_types.h"
+
+static inline uint64_t callStyleFunc(CStyleFunc fn, int count, int index, void* userdata) {
+	return fn(count, index, userdata);
+}
 */
 import "C"
 import (
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"sync"
 	"unsafe"
 
 	"github.com/charmbracelet/lipgloss"
@@ -20,7 +28,10 @@ var (
 	String   = &StringUtil{}
 	Validate = &ValidationUtil{}
 	Memory   = &MemoryUtil{
-		allocations: make(map[unsafe.Pointer]string),
+		enabled:     true,
+		complete:    true,
+		allocations: make(map[unsafe.Pointer]allocation),
+		categories:  make(map[string]*AllocationStats),
 	}
 	Style = &StyleUtil{}
 )
@@ -43,13 +54,40 @@ var CurrentLogLevel = LogLevelError
 
 type StringUtil struct{}
 type ValidationUtil struct{}
+
+// MemoryUtil tracks the C strings handed out to callers
 type MemoryUtil struct {
-	allocations map[unsafe.Pointer]string
+	sync.Mutex
+	enabled     bool
+	complete    bool // tracking has been on since startup
+	allocations map[unsafe.Pointer]allocation
+	categories  map[string]*AllocationStats
+
+	liveBytes      int
+	highWater      int
+	highWaterBytes int
 }
+
+type allocation struct {
+	category string
+	bytes    int
+}
+
+// AllocationStats counts the strings allocated under one category
+type AllocationStats struct {
+	Live           int
+	LiveBytes      int
+	HighWater      int
+	HighWaterBytes int
+	Total          int
+	TotalBytes     int
+}
+
 type StyleUtil struct{}
 
 //export SetLogLevel
 func SetLogLevel(level C.int) {
+	defer recoverPanic("SetLogLevel")
 	CurrentLogLevel = int(level)
 }
 
@@ -111,9 +149,9 @@ func (su *StringUtil) ToBool(i C.int) bool {
 	return i != 0
 }
 
-// Position validation
+// Position validation. The comparison is written so NaN fails it too.
 func (vu *ValidationUtil) Position(pos float64, name string) error {
-	if pos < 0 || pos > 1 {
+	if !(pos >= 0 && pos <= 1) {
 		return fmt.Errorf("invalid %s position: %f (must be between 0 and 1)", name, pos)
 	}
 	return nil
@@ -163,57 +201,160 @@ func (vu *ValidationUtil) Color(color string, op string) error {
 	return nil
 }
 
-// Track allocation
+// CString allocates a C copy of s and tracks it under category. The caller
+// frees it with FreeString.
+func (mu *MemoryUtil) CString(s, category string) *C.char {
+	cs := C.CString(s)
+	mu.Track(unsafe.Pointer(cs), category)
+	return cs
+}
+
+// SetEnabled switches tracking on or off. Pointers tracked before tracking
+// was switched off are still released by Untrack.
+func (mu *MemoryUtil) SetEnabled(enabled bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	if !enabled {
+		// Strings allocated from now on are unknown, so frees of unknown
+		// pointers can no longer be told apart from bogus ones
+		mu.complete = false
+	}
+	mu.enabled = enabled
+}
+
+// Track records a C string allocation under a category
 func (mu *MemoryUtil) Track(ptr unsafe.Pointer, desc string) {
-	if CurrentLogLevel >= LogLevelDebug {
-		mu.allocations[ptr] = desc
-		Log(LogLevelDebug, "allocated: %v (%s)", ptr, desc)
+	if ptr == nil {
+		return
+	}
+	size := int(C.strlen((*C.char)(ptr))) + 1
+	scopes.adoptString(ptr)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !mu.enabled {
+		return
+	}
+
+	mu.allocations[ptr] = allocation{category: desc, bytes: size}
+	stats, ok := mu.categories[desc]
+	if !ok {
+		stats = &AllocationStats{}
+		mu.categories[desc] = stats
 	}
+	stats.Live++
+	stats.LiveBytes += size
+	stats.HighWater = max(stats.HighWater, stats.Live)
+	stats.HighWaterBytes = max(stats.HighWaterBytes, stats.LiveBytes)
+	stats.Total++
+	stats.TotalBytes += size
+
+	mu.liveBytes += size
+	mu.highWater = max(mu.highWater, len(mu.allocations))
+	mu.highWaterBytes = max(mu.highWaterBytes, mu.liveBytes)
+	Log(LogLevelDebug, "allocated: %v (%s, %d bytes)", ptr, desc, size)
 }
 
-// Untrack allocation
+// Untrack forgets a tracked allocation before it is freed
 func (mu *MemoryUtil) Untrack(ptr unsafe.Pointer) {
-	if CurrentLogLevel >= LogLevelDebug {
-		if desc, ok := mu.allocations[ptr]; ok {
-			Log(LogLevelDebug, "freed: %v (%s)", ptr, desc)
-			delete(mu.allocations, ptr)
-		} else {
+	scopes.forgetString(ptr)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	a, ok := mu.allocations[ptr]
+	if !ok {
+		if mu.enabled && mu.complete {
 			Log(LogLevelWarn, "attempting to free untracked pointer: %v", ptr)
 		}
+		return
 	}
+
+	delete(mu.allocations, ptr)
+	stats := mu.categories[a.category]
+	stats.Live--
+	stats.LiveBytes -= a.bytes
+	mu.liveBytes -= a.bytes
+	Log(LogLevelDebug, "freed: %v (%s)", ptr, a.category)
+}
+
+// Stats returns a copy of the per-category allocation counters
+func (mu *MemoryUtil) Stats() map[string]AllocationStats {
+	mu.Lock()
+	defer mu.Unlock()
+
+	stats := make(map[string]AllocationStats, len(mu.categories))
+	for category, s := range mu.categories {
+		stats[category] = *s
+	}
+	return stats
 }
 
 // CheckMemoryLeaks returns information about potential memory leaks
 func (mu *MemoryUtil) CheckLeaks() (*C.char, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
 	if len(mu.allocations) == 0 {
 		return String.CString("No memory leaks detected")
 	}
 
 	var leaks string
-	for ptr, desc := range mu.allocations {
-		leaks += fmt.Sprintf("Leak: %v (%s)\n", ptr, desc)
+	for ptr, a := range mu.allocations {
+		leaks += fmt.Sprintf("Leak: %v (%s, %d bytes)\n", ptr, a.category, a.bytes)
 	}
 	return String.CString(leaks)
 }
 
 //export GetMemoryLeaks
-func GetMemoryLeaks() *C.char {
+func GetMemoryLeaks() (ret *C.char) {
+	defer recoverString("GetMemoryLeaks", &ret, "Error checking memory leaks")
 	cs, err := Memory.CheckLeaks()
 	if err != nil {
 		Log(LogLevelError, "Failed to get memory leaks: %v", err)
-		defaultCs, _ := String.CString("Error checking memory leaks")
-		return defaultCs
+		return Memory.CString("Error checking memory leaks", "memory leaks string")
 	}
+	Memory.Track(unsafe.Pointer(cs), "memory leaks string")
 	return cs
 }
 
+// SetMemoryTracking switches allocation tracking on (nonzero) or off.
+// Tracking is on by default and independent of the log level.
+//
+//export SetMemoryTracking
+func SetMemoryTracking(enabled C.int) {
+	defer recoverPanic("SetMemoryTracking")
+	Memory.SetEnabled(String.ToBool(enabled))
+}
+
+// GetMemoryStats reports live and total string allocations per category,
+// one category per line.
+//
+//export GetMemoryStats
+func GetMemoryStats() (ret *C.char) {
+	defer recoverString("GetMemoryStats", &ret, "")
+	stats := Memory.Stats()
+	categories := make([]string, 0, len(stats))
+	for category := range stats {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var b strings.Builder
+	for _, category := range categories {
+		s := stats[category]
+		fmt.Fprintf(&b, "%s: live %d (%d bytes), total %d (%d bytes)\n",
+			category, s.Live, s.LiveBytes, s.Total, s.TotalBytes)
+	}
+	if b.Len() == 0 {
+		b.WriteString("No allocations tracked\n")
+	}
+	return Memory.CString(b.String(), "memory stats string")
+}
+
 // SafeGet safely retrieves a style from the registry
 func (su *StyleUtil) SafeGet(id uint64, op string) (*lipgloss.Style, error) {
-	style := styleReg.Get(id)
-	if style == nil {
-		return nil, fmt.Errorf("style error (op=%s): style not found with ID %d", op, id)
-	}
-	return style, nil
+	return styleReg.Lookup(id, op)
 }
 
 // Register adds a style to the registry
@@ -221,6 +362,23 @@ func (su *StyleUtil) Register(style *lipgloss.Style) uint64 {
 	return styleReg.Register(style)
 }
 
+// Func adapts a C style callback into a function resolving the returned
+// style ID on every call. An ID of 0 or an unknown ID yields an empty style.
+func (su *StyleUtil) Func(fn C.CStyleFunc, userdata unsafe.Pointer, op string) func(count, index int) lipgloss.Style {
+	return func(count, index int) lipgloss.Style {
+		id := uint64(C.callStyleFunc(fn, C.int(count), C.int(index), userdata))
+		if id == 0 {
+			return lipgloss.NewStyle()
+		}
+		style, err := su.SafeGet(id, op)
+		if err != nil {
+			Log(LogLevelError, "%s callback error: %v", op, err)
+			return lipgloss.NewStyle()
+		}
+		return *style
+	}
+}
+
 // Free removes a style from the registry
 func (su *StyleUtil) Free(id uint64) {
 	styleReg.Remove(id)
@@ -0,0 +1,53 @@
+//go:build lipgloss_testhelpers
+
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemoryStats(t *testing.T) {
+	str := StyleString(keep(t, NewStyle()))
+	stats := takeString(GetMemoryStats())
+	FreeString(str)
+	if !strings.Contains(stats, "StyleString result: live 1 (1 bytes)") {
+		t.Errorf("GetMemoryStats = %q", stats)
+	}
+
+	leaks := takeString(GetMemoryLeaks())
+	if leaks != "No memory leaks detected" {
+		t.Errorf("GetMemoryLeaks with nothing live = %q", leaks)
+	}
+}
+
+func TestMemoryTracking(t *testing.T) {
+	t.Cleanup(func() {
+		Memory.Lock()
+		Memory.enabled, Memory.complete = true, true
+		Memory.Unlock()
+	})
+
+	SetMemoryTracking(0)
+	untracked := StyleString(keep(t, NewStyle()))
+	if strings.Contains(takeString(GetMemoryLeaks()), "StyleString") {
+		t.Error("a string allocated with tracking off was tracked")
+	}
+	SetMemoryTracking(1)
+
+	// Freeing it is quiet, since tracking has not been on all along
+	if got := logged(func() { FreeString(untracked) }); got != "" {
+		t.Errorf("freeing an untracked string logged %q", got)
+	}
+}
+
+func TestSetLogLevel(t *testing.T) {
+	t.Cleanup(func() { SetLogLevel(LogLevelError) })
+
+	SetLogLevel(LogLevelDebug)
+	expectLog(t, "Registered new style", func() { FreeStyle(NewStyle()) })
+	SetLogLevel(-1)
+	if got := logged(func() { FreeStyle(stale()) }); got != "" {
+		t.Errorf("with logging off a double free logged %q", got)
+	}
+}